@@ -0,0 +1,128 @@
+// Package checksum hashes files and readers, and verifies them against
+// checksum files in the format of sha1sum and sha256sum.
+// It is the sha1sum function of files/sha1.go made reusable.
+package checksum
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// Algorithm names a hash function.
+type Algorithm string
+
+const (
+	SHA1   Algorithm = "sha1"
+	SHA256 Algorithm = "sha256"
+	SHA512 Algorithm = "sha512"
+)
+
+// New returns a new hash.Hash computing a.
+func New(a Algorithm) (hash.Hash, error) {
+	switch a {
+	case SHA1:
+		return sha1.New(), nil
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("checksum: unknown algorithm %q", a)
+}
+
+// ForHex guesses the algorithm of a hex encoded sum from its length.
+func ForHex(sum string) (Algorithm, error) {
+	switch len(sum) {
+	case 2 * sha1.Size:
+		return SHA1, nil
+	case 2 * sha256.Size:
+		return SHA256, nil
+	case 2 * sha512.Size:
+		return SHA512, nil
+	}
+	return "", fmt.Errorf("checksum: %q is not a sha1, sha256 or sha512 sum", sum)
+}
+
+// Reader returns the hex encoded a sum of everything read from r.
+func Reader(r io.Reader, a Algorithm) (string, error) {
+	h, err := New(a)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// File returns the hex encoded a sum of the content of filename.
+func File(filename string, a Algorithm) (string, error) {
+	// idiom: acquire a resource, check for error, defer release
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return Reader(f, a)
+}
+
+// Verify reports whether r hashes to want, a hex encoded sum
+// whose algorithm is guessed from its length.
+func Verify(r io.Reader, want string) error {
+	want = strings.ToLower(strings.TrimSpace(want))
+	a, err := ForHex(want)
+	if err != nil {
+		return err
+	}
+	got, err := Reader(r, a)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return &MismatchError{Algorithm: a, Want: want, Got: got}
+	}
+	return nil
+}
+
+// MismatchError is returned when the content doesn't hash to the expected sum.
+type MismatchError struct {
+	Algorithm Algorithm
+	Want, Got string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("checksum: %s mismatch: want %s, got %s", e.Algorithm, e.Want, e.Got)
+}
+
+// ParseSums reads a checksum file written by sha256sum and friends,
+// one "<hex sum>  <file name>" per line, and maps the file names to their sums.
+// A "*" before the name (binary mode) is ignored, as are blank lines and # comments.
+func ParseSums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("checksum: line %d: want \"<sum>  <name>\", got %q", n, line)
+		}
+		name = strings.TrimPrefix(strings.TrimLeft(name, " "), "*")
+		if _, err := ForHex(sum); err != nil {
+			// ForHex's error says the same with the package prefix, without the line
+			return nil, fmt.Errorf("checksum: line %d: %q is not a sha1, sha256 or sha512 sum", n, sum)
+		}
+		sums[name] = strings.ToLower(sum)
+	}
+	return sums, sc.Err()
+}
@@ -0,0 +1,74 @@
+package checksum_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"main/files/checksum"
+)
+
+// sums of "hello\n", as printed by sha1sum, sha256sum and sha512sum
+const (
+	helloSHA1   = "f572d396fae9206628714fb2ce00f72e94f2258f"
+	helloSHA256 = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+	helloSHA512 = "e7c22b994c59d9cf2b48e549b1e24666636045930d3da7c1acb299d1c3b7f931f94aae41edda2c2b207a36e10f8bcb8d45223e54878f5b316e7ce3b6bc019629"
+)
+
+func TestVerify(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		want     string
+		mismatch bool
+		err      bool
+	}{
+		{"sha1", helloSHA1, false, false},
+		{"sha256", helloSHA256, false, false},
+		{"sha512", helloSHA512, false, false},
+		{"upper case and spaces", "  " + strings.ToUpper(helloSHA256) + "\n", false, false},
+		{"other content", strings.Replace(helloSHA256, "5", "6", 1), true, false},
+		{"unknown length", helloSHA256[:40-2], false, true},
+	} {
+		err := checksum.Verify(strings.NewReader("hello\n"), c.want)
+		var mismatch *checksum.MismatchError
+		switch {
+		case c.mismatch && !errors.As(err, &mismatch):
+			t.Errorf("%s: got %v, want a MismatchError", c.name, err)
+		case c.err && (err == nil || errors.As(err, &mismatch)):
+			t.Errorf("%s: got %v, want an error other than a mismatch", c.name, err)
+		case !c.mismatch && !c.err && err != nil:
+			t.Errorf("%s: %v", c.name, err)
+		}
+	}
+}
+
+func TestParseSums(t *testing.T) {
+	file := `# SHA256 checksums
+` + helloSHA256 + `  hello.txt
+` + strings.ToUpper(helloSHA1) + ` *hello.bin
+
+` + helloSHA512 + `  dir/with space.txt
+`
+	sums, err := checksum.ParseSums(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"hello.txt": helloSHA256, "hello.bin": helloSHA1, "dir/with space.txt": helloSHA512}
+	if len(sums) != len(want) {
+		t.Errorf("got %v, want %v", sums, want)
+	}
+	for name, sum := range want {
+		if sums[name] != sum {
+			t.Errorf("%s: got %q, want %q", name, sums[name], sum)
+		}
+	}
+
+	for _, c := range []struct{ file, err string }{
+		{helloSHA256 + "\n", `checksum: line 1: want "<sum>  <name>"`},
+		{"# header\nabc123  hello.txt\n", `checksum: line 2: "abc123" is not a sha1, sha256 or sha512 sum`},
+	} {
+		if _, err := checksum.ParseSums(strings.NewReader(c.file)); err == nil || !strings.HasPrefix(err.Error(), c.err) {
+			t.Errorf("ParseSums(%q): got %v, want %s...", c.file, err, c.err)
+		}
+	}
+}
@@ -2,12 +2,13 @@ package main
 
 import (
 	"compress/gzip"
-	"crypto/sha1"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"strings"
+
+	"main/files/checksum"
 )
 
 func main() {
@@ -68,13 +69,6 @@ func sha1sum(filename string) (string, error) {
 	// io.CopyN(os.Stdout, r, 100)
 	// fmt.Println()
 
-	w := sha1.New()
-	if _, err := io.Copy(w, ir); err != nil {
-		return "", err
-	}
-
-	hashBytes := w.Sum(nil)
-
-	// fmt.Printf("buf: %v\n", buf[0:32])
-	return fmt.Sprintf("%x", hashBytes), nil
+	// the hashing itself lives in files/checksum so other programs can verify downloads with it
+	return checksum.Reader(ir, checksum.SHA1)
 }
@@ -2,4 +2,7 @@ module main
 
 go 1.21.4
 
-require golang.org/x/exp v0.0.0-20231127185646-65229373498e
+require (
+	golang.org/x/exp v0.0.0-20231127185646-65229373498e
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,326 @@
+// audit compares the members, outside collaborators, teams and team
+// repository permissions of a GitHub organization with a desired state file,
+// and reports members without two-factor authentication.
+//
+//	GITHUB_TOKEN=... go run ./http/audit -desired acme.yaml
+//
+// The desired state is YAML (or JSON, which YAML accepts too):
+//
+//	org: acme
+//	admins: [alice]
+//	members: [bob, carol]
+//	outside_collaborators: [dave]
+//	require_2fa: true
+//	teams:
+//	  backend:
+//	    members: [bob]
+//	    repos:
+//	      api: write
+//	      web: read
+//
+// Every discrepancy is printed on its own line and the exit status is 1 if there is any.
+// Point -base-url at a fake server to run it offline.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"main/http/github"
+)
+
+// Desired is the desired state file.
+type Desired struct {
+	Org                  string                 `yaml:"org"`
+	Admins               []string               `yaml:"admins"`
+	Members              []string               `yaml:"members"`
+	OutsideCollaborators []string               `yaml:"outside_collaborators"`
+	Require2FA           bool                   `yaml:"require_2fa"`
+	Teams                map[string]DesiredTeam `yaml:"teams"`
+}
+
+type DesiredTeam struct {
+	Members []string `yaml:"members"`
+	// Repos maps repository names to a role: read, triage, write, maintain or admin.
+	Repos map[string]string `yaml:"repos"`
+}
+
+// Actual is what the organization looks like on GitHub.
+type Actual struct {
+	Admins               []string
+	Members              []string // admins included
+	OutsideCollaborators []string
+	No2FA                []string
+	Teams                map[string]ActualTeam // by slug
+}
+
+type ActualTeam struct {
+	Members []string
+	Repos   map[string]string
+}
+
+func main() {
+	var (
+		desiredPath = flag.String("desired", "", "desired state `file` (YAML or JSON)")
+		org         = flag.String("org", "", "organization, overrides the one in the desired state")
+		baseURL     = flag.String("base-url", github.DefaultBaseURL, "GitHub API root")
+	)
+	flag.Parse()
+	log.SetFlags(0)
+	if *desiredPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	d, err := loadDesired(*desiredPath)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	if *org != "" {
+		d.Org = *org
+	}
+	if d.Org == "" {
+		log.Fatalf("error: no organization, set org in %s or use -org", *desiredPath)
+	}
+
+	c := github.NewClient()
+	c.BaseURL = *baseURL
+	c.Token = os.Getenv("GITHUB_TOKEN")
+
+	a, err := fetch(context.Background(), c, d.Org, d.Require2FA)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	issues := audit(d, a)
+	report(os.Stdout, d.Org, issues)
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadDesired(path string) (*Desired, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var d Desired
+	if err := yaml.Unmarshal(b, &d); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &d, nil
+}
+
+// fetch reads the actual state of org. The 2FA filter needs an owner token,
+// so it is only queried when the desired state requires 2FA.
+func fetch(ctx context.Context, c *github.Client, org string, with2FA bool) (*Actual, error) {
+	a := &Actual{Teams: make(map[string]ActualTeam)}
+	var err error
+
+	if a.Members, err = logins(c.ListOrgMembers(ctx, org, nil)); err != nil {
+		return nil, fmt.Errorf("members: %w", err)
+	}
+	if a.Admins, err = logins(c.ListOrgMembers(ctx, org, &github.MemberFilter{Role: "admin"})); err != nil {
+		return nil, fmt.Errorf("admins: %w", err)
+	}
+	if a.OutsideCollaborators, err = logins(c.ListOutsideCollaborators(ctx, org)); err != nil {
+		return nil, fmt.Errorf("outside collaborators: %w", err)
+	}
+	if with2FA {
+		if a.No2FA, err = logins(c.ListOrgMembers(ctx, org, &github.MemberFilter{TwoFactorDisabled: true})); err != nil {
+			return nil, fmt.Errorf("2fa: %w", err)
+		}
+	}
+
+	teams, err := github.Collect(c.ListTeams(ctx, org))
+	if err != nil {
+		return nil, fmt.Errorf("teams: %w", err)
+	}
+	for _, t := range teams {
+		members, err := logins(c.ListTeamMembers(ctx, org, t.Slug))
+		if err != nil {
+			return nil, fmt.Errorf("team %s: %w", t.Slug, err)
+		}
+		repos, err := github.Collect(c.ListTeamRepos(ctx, org, t.Slug))
+		if err != nil {
+			return nil, fmt.Errorf("team %s: %w", t.Slug, err)
+		}
+
+		at := ActualTeam{Members: members, Repos: make(map[string]string)}
+		for _, r := range repos {
+			at.Repos[strings.ToLower(r.Name)] = r.Role()
+		}
+		a.Teams[strings.ToLower(t.Slug)] = at
+	}
+	return a, nil
+}
+
+func logins(seq func(yield func(github.Account, error) bool)) ([]string, error) {
+	accounts, err := github.Collect(seq)
+	out := make([]string, len(accounts))
+	for i, a := range accounts {
+		out[i] = a.Login
+	}
+	return out, err
+}
+
+// Issue is one discrepancy between the desired and the actual state.
+type Issue struct {
+	Subject string // e.g. "member bob" or "team backend"
+	Problem string
+}
+
+// audit lists every discrepancy, logins and names are compared case insensitively.
+func audit(d *Desired, a *Actual) []Issue {
+	var issues []Issue
+	add := func(subject, format string, args ...any) {
+		issues = append(issues, Issue{subject, fmt.Sprintf(format, args...)})
+	}
+
+	admins := set(d.Admins)
+	wantMembers := set(append(append([]string{}, d.Admins...), d.Members...))
+	gotAdmins := set(a.Admins)
+
+	extra, missing := diff(wantMembers, set(a.Members))
+	for _, m := range extra {
+		add("member "+m, "is a member but not in the desired state")
+	}
+	for _, m := range missing {
+		add("member "+m, "is in the desired state but not a member")
+	}
+	for _, m := range a.Members {
+		k := strings.ToLower(m)
+		switch {
+		case admins[k] && !gotAdmins[k]:
+			add("member "+m, "should be an admin but is a member")
+		case !admins[k] && gotAdmins[k] && wantMembers[k]:
+			add("member "+m, "is an admin but should be a member")
+		}
+	}
+
+	extra, missing = diff(set(d.OutsideCollaborators), set(a.OutsideCollaborators))
+	for _, m := range extra {
+		add("outside collaborator "+m, "has access but is not in the desired state")
+	}
+	for _, m := range missing {
+		add("outside collaborator "+m, "is in the desired state but has no access")
+	}
+
+	if d.Require2FA {
+		for _, m := range a.No2FA {
+			add("member "+m, "has two-factor authentication disabled")
+		}
+	}
+
+	for name, dt := range d.Teams {
+		at, ok := a.Teams[strings.ToLower(name)]
+		if !ok {
+			add("team "+name, "does not exist")
+			continue
+		}
+
+		extra, missing := diff(set(dt.Members), set(at.Members))
+		for _, m := range extra {
+			add("team "+name, "has unexpected member %s", m)
+		}
+		for _, m := range missing {
+			add("team "+name, "is missing member %s", m)
+		}
+
+		for repo, role := range dt.Repos {
+			want := normalizeRole(role)
+			got, ok := at.Repos[strings.ToLower(repo)]
+			switch {
+			case !ok:
+				add("team "+name, "has no access to %s, want %s", repo, want)
+			case got != want:
+				add("team "+name, "has %s on %s, want %s", got, repo, want)
+			}
+		}
+		wantRepos := lowerKeys(dt.Repos)
+		for repo, got := range at.Repos {
+			if !wantRepos[repo] {
+				add("team "+name, "has unexpected %s access to %s", got, repo)
+			}
+		}
+	}
+	wantTeams := lowerKeys(d.Teams)
+	for slug := range a.Teams {
+		if !wantTeams[slug] {
+			add("team "+slug, "exists but is not in the desired state")
+		}
+	}
+
+	// teams come from maps, sort for a stable report
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Subject != issues[j].Subject {
+			return issues[i].Subject < issues[j].Subject
+		}
+		return issues[i].Problem < issues[j].Problem
+	})
+	return issues
+}
+
+func report(w io.Writer, org string, issues []Issue) {
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "%s: matches the desired state\n", org)
+		return
+	}
+	fmt.Fprintf(w, "%s: %d discrepancies\n", org, len(issues))
+	for _, is := range issues {
+		fmt.Fprintf(w, "  %s %s\n", is.Subject, is.Problem)
+	}
+}
+
+// normalizeRole maps the REST permission names to the role names GitHub reports.
+func normalizeRole(r string) string {
+	switch r = strings.ToLower(r); r {
+	case "pull":
+		return "read"
+	case "push":
+		return "write"
+	}
+	return r
+}
+
+// set maps the lower cased names to true.
+func set(names []string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[strings.ToLower(n)] = true
+	}
+	return m
+}
+
+// diff returns the sorted names only in got (extra), and only in want (missing).
+func diff(want, got map[string]bool) (extra, missing []string) {
+	for n := range got {
+		if !want[n] {
+			extra = append(extra, n)
+		}
+	}
+	for n := range want {
+		if !got[n] {
+			missing = append(missing, n)
+		}
+	}
+	sort.Strings(extra)
+	sort.Strings(missing)
+	return extra, missing
+}
+
+func lowerKeys[V any](m map[string]V) map[string]bool {
+	out := make(map[string]bool, len(m))
+	for k := range m {
+		out[strings.ToLower(k)] = true
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"main/http/fakegithub"
+	"main/http/github"
+)
+
+// acme returns a seed for an organization of n members, more than a page of 100.
+func acme(n int) *fakegithub.Seed {
+	members := []string{"alice"}
+	for i := 0; i < n-1; i++ {
+		members = append(members, fmt.Sprintf("m%03d", i))
+	}
+	return &fakegithub.Seed{Orgs: map[string]*fakegithub.Org{
+		"acme": {
+			Members:              members,
+			Admins:               []string{"alice", "m000"},
+			OutsideCollaborators: []string{"dave"},
+			No2FA:                []string{"m007"},
+			Teams: []fakegithub.Team{
+				{Name: "Backend", Slug: "backend", Members: []string{"alice", "m001"}, Repos: map[string]string{"api": "write", "web": "admin"}},
+				{Name: "Infra", Slug: "infra", Members: []string{"m002"}},
+			},
+		},
+	}}
+}
+
+// serve runs a fake GitHub for seed and returns a client of it and the
+// requests it got, by path.
+func serve(t *testing.T, seed *fakegithub.Seed) (*github.Client, map[string]int) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	fake := fakegithub.New(seed)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := github.NewClient()
+	c.BaseURL = srv.URL
+	return c, requests
+}
+
+func TestFetchPaginates(t *testing.T) {
+	c, requests := serve(t, acme(250))
+	a, err := fetch(context.Background(), c, "acme", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Members) != 250 || a.Members[249] != "m248" {
+		t.Fatalf("got %d members, want 250 through m248", len(a.Members))
+	}
+	// the members, the admins and the 2FA filter, the members in 3 pages
+	if n := requests["/orgs/acme/members"]; n != 5 {
+		t.Errorf("%d requests for the members, want 5", n)
+	}
+	if got := strings.Join(a.Admins, " "); got != "alice m000" {
+		t.Errorf("admins %q", got)
+	}
+	if got := strings.Join(a.No2FA, " "); got != "m007" {
+		t.Errorf("members without 2FA %q", got)
+	}
+	if got := a.Teams["backend"].Repos["web"]; got != "admin" {
+		t.Errorf("backend role on web %q, want admin", got)
+	}
+}
+
+func TestAuditReport(t *testing.T) {
+	c, _ := serve(t, acme(250))
+	a, err := fetch(context.Background(), c, "acme", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Desired{
+		Org:                  "acme",
+		Admins:               []string{"Alice", "m001"},
+		OutsideCollaborators: []string{"erin"},
+		Require2FA:           true,
+		Teams: map[string]DesiredTeam{
+			"backend": {Members: []string{"alice", "m001"}, Repos: map[string]string{"api": "push", "web": "write"}},
+			"docs":    {},
+		},
+	}
+	for i := 0; i < 248; i++ { // all but m248
+		d.Members = append(d.Members, fmt.Sprintf("m%03d", i))
+	}
+	d.Members = append(d.Members, "ghost")
+
+	var b strings.Builder
+	report(&b, d.Org, audit(d, a))
+	want := `acme: 10 discrepancies
+  member ghost is in the desired state but not a member
+  member m000 is an admin but should be a member
+  member m001 should be an admin but is a member
+  member m007 has two-factor authentication disabled
+  member m248 is a member but not in the desired state
+  outside collaborator dave has access but is not in the desired state
+  outside collaborator erin is in the desired state but has no access
+  team backend has admin on web, want write
+  team docs does not exist
+  team infra exists but is not in the desired state
+`
+	if got := b.String(); got != want {
+		t.Errorf("report:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAuditMatches(t *testing.T) {
+	d := &Desired{Org: "acme", Admins: []string{"alice"}}
+	a := &Actual{Admins: []string{"alice"}, Members: []string{"ALICE"}}
+	var b strings.Builder
+	report(&b, d.Org, audit(d, a))
+	if got := b.String(); got != "acme: matches the desired state\n" {
+		t.Errorf("report %q", got)
+	}
+}
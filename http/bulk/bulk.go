@@ -0,0 +1,203 @@
+// bulk looks up many GitHub users concurrently.
+//
+//	go run ./http/bulk -format csv alice bob
+//	go run ./http/bulk -f users.txt -workers 8
+//	cat users.txt | go run ./http/bulk -format json
+//
+// Usernames come from the arguments, from -f (one per line, "-" for stdin),
+// or from stdin when neither is given. Successes are written to stdout,
+// per-user errors to stderr, and the exit status is 1 if any lookup failed.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"main/http/github"
+)
+
+// result is the outcome of one lookup, exactly one of user and err is set.
+type result struct {
+	login string
+	user  *github.User
+	err   error
+}
+
+// record is what we print for a user.
+type record struct {
+	Login       string    `json:"login"`
+	Name        string    `json:"name"`
+	PublicRepos int       `json:"public_repos"`
+	Followers   int       `json:"followers"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func main() {
+	var (
+		file    = flag.String("f", "", "read usernames from `file`, one per line (- for stdin)")
+		workers = flag.Int("workers", 4, "number of concurrent lookups")
+		format  = flag.String("format", "table", "output format: table, json or csv")
+		rps     = flag.Float64("rps", 5, "requests per second shared by all workers")
+		baseURL = flag.String("base-url", github.DefaultBaseURL, "GitHub API root")
+	)
+	flag.Parse()
+	log.SetFlags(0)
+
+	// check the format now rather than after every lookup has run
+	write, err := formatWriter(*format)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	names, err := usernames(flag.Args(), *file)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	c := github.NewClient()
+	c.BaseURL = *baseURL
+	c.Token = os.Getenv("GITHUB_TOKEN")
+	// one limiter for the whole pool, so adding workers doesn't add load on GitHub
+	c.Limiter = github.NewLimiter(*rps, *workers)
+
+	results := lookup(context.Background(), c, names, *workers)
+
+	var ok []record
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", r.login, r.err)
+			failed++
+			continue
+		}
+		ok = append(ok, record{r.user.Login, r.user.Name, r.user.NumOfRepos, r.user.Followers, r.user.CreatedAt})
+	}
+
+	if err := write(os.Stdout, ok); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// lookup fetches names through a pool of workers.
+// The results are in the same order as names.
+func lookup(ctx context.Context, c *github.Client, names []string, workers int) []result {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]result, len(names))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				u, err := c.User(ctx, names[i])
+				// each worker writes its own index, no lock needed
+				results[i] = result{login: names[i], user: u, err: err}
+			}
+		}()
+	}
+
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// usernames collects the names to look up, skipping blanks, # comments and duplicates.
+func usernames(args []string, file string) ([]string, error) {
+	names := args
+	if file != "" || len(args) == 0 {
+		var r io.Reader = os.Stdin
+		if file != "" && file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			names = append(names, s.Text())
+		}
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		key := strings.ToLower(n) // GitHub logins are case insensitive
+		if n == "" || strings.HasPrefix(n, "#") || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// formatWriter returns the function writing records in format.
+func formatWriter(format string) (func(io.Writer, []record) error, error) {
+	switch format {
+	case "json":
+		return writeJSON, nil
+	case "csv":
+		return writeCSV, nil
+	case "table":
+		return writeTable, nil
+	}
+	return nil, fmt.Errorf("unknown format %q, want table, json or csv", format)
+}
+
+func writeJSON(w io.Writer, recs []record) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if recs == nil {
+		recs = []record{} // [] rather than null
+	}
+	return enc.Encode(recs)
+}
+
+func writeCSV(w io.Writer, recs []record) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"login", "name", "public_repos", "followers", "created_at"})
+	for _, r := range recs {
+		cw.Write([]string{r.Login, r.Name, strconv.Itoa(r.PublicRepos), strconv.Itoa(r.Followers), r.CreatedAt.Format(time.RFC3339)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTable(w io.Writer, recs []record) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOGIN\tNAME\tREPOS\tFOLLOWERS\tCREATED")
+	for _, r := range recs {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", r.Login, r.Name, r.PublicRepos, r.Followers, r.CreatedAt.Format("2006-01-02"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"main/http/fakegithub"
+	"main/http/github"
+)
+
+var recs = []record{
+	{"alice", "Alice, A.", 3, 10, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+	{"bob", "", 0, 1, time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC)},
+}
+
+func TestFormatWriter(t *testing.T) {
+	for _, c := range []struct {
+		format string
+		recs   []record
+		want   string
+	}{
+		{"table", recs, `LOGIN  NAME       REPOS  FOLLOWERS  CREATED
+alice  Alice, A.  3      10         2020-01-02
+bob               0      1          2021-06-07
+`},
+		{"csv", recs, `login,name,public_repos,followers,created_at
+alice,"Alice, A.",3,10,2020-01-02T03:04:05Z
+bob,,0,1,2021-06-07T00:00:00Z
+`},
+		{"json", recs[1:], `[
+  {
+    "login": "bob",
+    "name": "",
+    "public_repos": 0,
+    "followers": 1,
+    "created_at": "2021-06-07T00:00:00Z"
+  }
+]
+`},
+		{"json", nil, "[]\n"},
+	} {
+		write, err := formatWriter(c.format)
+		if err != nil {
+			t.Fatalf("%s: %v", c.format, err)
+		}
+		var b strings.Builder
+		if err := write(&b, c.recs); err != nil {
+			t.Fatalf("%s: %v", c.format, err)
+		}
+		if b.String() != c.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", c.format, b.String(), c.want)
+		}
+	}
+
+	for _, format := range []string{"", "yaml", "JSON"} {
+		if _, err := formatWriter(format); err == nil {
+			t.Errorf("format %q accepted", format)
+		}
+	}
+}
+
+func TestUsernames(t *testing.T) {
+	got, err := usernames([]string{"alice", " Bob ", "", "# comment", "ALICE", "carol"}, "")
+	if want := []string{"alice", "Bob", "carol"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("from the arguments = %q, %v, want %q", got, err, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "users.txt")
+	if err := os.WriteFile(path, []byte("# team\ndave\n\nbob\nerin\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = usernames([]string{"bob"}, path)
+	if want := []string{"bob", "dave", "erin"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("from a file = %q, %v, want %q", got, err, want)
+	}
+
+	if _, err := usernames(nil, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("a missing file succeeded")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	srv := httptest.NewServer(fakegithub.New(&fakegithub.Seed{
+		Users: []map[string]any{{"login": "alice", "name": "Alice"}, {"login": "bob"}, {"login": "carol"}},
+	}))
+	defer srv.Close()
+	c := github.NewClient()
+	c.BaseURL = srv.URL
+
+	names := []string{"carol", "nobody", "alice", "bob"}
+	for _, workers := range []int{0, 1, 3, 10} {
+		results := lookup(context.Background(), c, names, workers)
+		if len(results) != len(names) {
+			t.Fatalf("%d workers: %d results, want %d", workers, len(results), len(names))
+		}
+		for i, r := range results {
+			if r.login != names[i] {
+				t.Errorf("%d workers: result %d is %s, want %s", workers, i, r.login, names[i])
+			}
+			if names[i] == "nobody" {
+				if !errors.Is(r.err, github.ErrNotFound) || r.user != nil {
+					t.Errorf("%d workers: nobody = %v, %v, want ErrNotFound", workers, r.user, r.err)
+				}
+				continue
+			}
+			if r.err != nil || r.user == nil || r.user.Login != names[i] {
+				t.Errorf("%d workers: %s = %+v, %v", workers, names[i], r.user, r.err)
+			}
+		}
+	}
+}
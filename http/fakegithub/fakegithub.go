@@ -0,0 +1,592 @@
+// Package fakegithub is a fake GitHub REST API serving users, repositories,
+// followers and organizations from a seed file. It paginates with Link
+// headers, sends rate limit headers and ETags, and can inject errors.
+//
+// Use it in tests with httptest:
+//
+//	srv := httptest.NewServer(fakegithub.New(seed))
+//	c := github.NewClient()
+//	c.BaseURL = srv.URL
+//
+// or run it standalone with http/fakeserver.
+package fakegithub
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Seed is the content of the seed file. Users and repositories are kept as
+// raw JSON objects so any field GitHub sends can be seeded.
+//
+//	{
+//	  "users": [{"login": "alice", "name": "Alice", "created_at": "2020-01-01T00:00:00Z"}],
+//	  "repos": {"alice": [{"name": "api", "stargazers_count": 3, "language": "Go"}]},
+//	  "followers": {"alice": ["bob"]},
+//	  "orgs": {"acme": {"members": ["alice"], "admins": ["alice"], "teams": [{"slug": "core", "members": ["alice"], "repos": {"api": "write"}}]}},
+//	  "readmes": {"alice/api": "# API\n"},
+//	  "releases": {"alice/api": [{"tag_name": "v1.0.0", "body": "First!", "assets": [{"name": "api.txt", "content": "hello"}]}]},
+//	  "rate_limit": 60,
+//	  "errors": [{"path": "/users/flaky", "status": 502, "count": 2}]
+//	}
+type Seed struct {
+	Users     []map[string]any            `json:"users"`
+	Repos     map[string][]map[string]any `json:"repos"`
+	Followers map[string][]string         `json:"followers"`
+	Orgs      map[string]*Org             `json:"orgs"`
+	// Readmes maps "owner/repo" to the Markdown of its README.
+	Readmes map[string]string `json:"readmes"`
+	// Releases maps "owner/repo" to its releases, newest first. An asset's
+	// "content" is what downloading it returns, its id and size are filled in.
+	Releases map[string][]map[string]any `json:"releases"`
+	// RateLimit is the number of requests allowed per hour, 0 means 5000.
+	RateLimit int `json:"rate_limit"`
+	// Errors are injected before the normal handling, the first matching rule wins.
+	Errors []*ErrorRule `json:"errors"`
+}
+
+// Org is a seeded organization.
+type Org struct {
+	Members              []string `json:"members"`
+	Admins               []string `json:"admins"`
+	OutsideCollaborators []string `json:"outside_collaborators"`
+	// No2FA are the members listed by filter=2fa_disabled.
+	No2FA []string `json:"no_2fa"`
+	Teams []Team   `json:"teams"`
+}
+
+type Team struct {
+	Name    string   `json:"name"`
+	Slug    string   `json:"slug"`
+	Members []string `json:"members"`
+	// Repos maps repository names to the team's role on them.
+	Repos map[string]string `json:"repos"`
+}
+
+// ErrorRule makes matching requests fail.
+type ErrorRule struct {
+	// Method matches any method when empty.
+	Method string `json:"method"`
+	// Path is matched exactly, or as a prefix when it ends with "*".
+	Path   string `json:"path"`
+	Status int    `json:"status"`
+	// Message is the "message" of the error body, the status text when empty.
+	Message string `json:"message"`
+	// RetryAfter, in seconds, is sent as the Retry-After header when set.
+	RetryAfter int `json:"retry_after"`
+	// Count limits the rule to the first Count matching requests, 0 means always.
+	Count int `json:"count"`
+	// Probability makes the rule fire randomly, 0 means always.
+	Probability float64 `json:"probability"`
+
+	hits int
+}
+
+// LoadSeed reads a seed file.
+func LoadSeed(path string) (*Seed, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Seed
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Server is the fake API, an http.Handler.
+type Server struct {
+	seed   *Seed
+	users  map[string]map[string]any // by lower cased login
+	assets map[string][]byte         // content by asset id
+
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// New returns a Server answering from seed.
+func New(seed *Seed) *Server {
+	s := &Server{seed: seed, users: make(map[string]map[string]any), limit: seed.RateLimit}
+	if s.limit <= 0 {
+		s.limit = 5000
+	}
+	s.remaining = s.limit
+	for _, u := range seed.Users {
+		login, _ := u["login"].(string)
+		s.users[strings.ToLower(login)] = u
+	}
+
+	s.assets = make(map[string][]byte)
+	id := 1
+	for _, releases := range seed.Releases {
+		for _, rel := range releases {
+			if _, ok := rel["id"]; !ok {
+				rel["id"] = id
+				id++
+			}
+			assets, _ := rel["assets"].([]any)
+			for _, a := range assets {
+				a, ok := a.(map[string]any)
+				if !ok {
+					continue
+				}
+				if _, ok := a["id"]; !ok {
+					a["id"] = id
+					id++
+				}
+				content, _ := a["content"].(string)
+				delete(a, "content")
+				a["size"] = len(content)
+				s.assets[fmt.Sprint(a["id"])] = []byte(content)
+			}
+			if rel["assets"] == nil {
+				rel["assets"] = []any{}
+			}
+		}
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rule := s.matchError(r); rule != nil {
+		if rule.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(rule.RetryAfter))
+		}
+		msg := rule.Message
+		if msg == "" {
+			msg = http.StatusText(rule.Status)
+		}
+		writeError(w, rule.Status, msg)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	v, err := s.route(r)
+	if err != nil {
+		writeError(w, err.status, err.msg)
+		return
+	}
+	body, contentType := v.raw, v.contentType
+	if body == nil {
+		var merr error
+		if body, merr = json.Marshal(v.body); merr != nil {
+			writeError(w, http.StatusInternalServerError, merr.Error())
+			return
+		}
+		contentType = "application/json; charset=utf-8"
+	}
+
+	// conditional requests don't count against the rate limit, like on GitHub
+	etag := fmt.Sprintf(`W/"%x"`, sha1.Sum(body))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		s.rateHeaders(w, false)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if !s.rateHeaders(w, true) {
+		writeError(w, http.StatusForbidden, "API rate limit exceeded for fake client.")
+		return
+	}
+
+	if v.total > 0 {
+		if link := linkHeader(r, v.page, v.perPage, v.total); link != "" {
+			w.Header().Set("Link", link)
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+// result is what a route answers: a body, and for lists the page it is.
+type result struct {
+	body                 any
+	page, perPage, total int
+	// raw, when set, is sent as is instead of body as JSON.
+	raw         []byte
+	contentType string
+}
+
+type apiError struct {
+	status int
+	msg    string
+}
+
+var notFound = &apiError{http.StatusNotFound, "Not Found"}
+
+func (s *Server) route(r *http.Request) (*result, *apiError) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	q := r.URL.Query()
+
+	switch {
+	case len(parts) == 1 && parts[0] == "rate_limit":
+		s.mu.Lock()
+		core := map[string]any{"limit": s.limit, "remaining": s.remaining, "reset": s.reset.Unix()}
+		s.mu.Unlock()
+		return &result{body: map[string]any{"resources": map[string]any{"core": core}, "rate": core}}, nil
+
+	case len(parts) >= 2 && parts[0] == "users":
+		u, ok := s.users[strings.ToLower(parts[1])]
+		if !ok {
+			return nil, notFound
+		}
+		login := u["login"].(string)
+		switch {
+		case len(parts) == 2:
+			return &result{body: s.user(login, u)}, nil
+		case len(parts) == 3 && parts[2] == "repos":
+			return paginate(q, sortRepos(s.seed.Repos[login], q.Get("sort"), q.Get("direction")))
+		case len(parts) == 3 && parts[2] == "followers":
+			return paginate(q, accounts(s.seed.Followers[login]))
+		}
+
+	case len(parts) >= 4 && parts[0] == "repos":
+		return s.routeRepo(r, parts[1]+"/"+parts[2], parts[3:])
+
+	case len(parts) >= 3 && parts[0] == "orgs":
+		org, ok := s.seed.Orgs[parts[1]]
+		if !ok {
+			return nil, notFound
+		}
+		switch {
+		case len(parts) == 3 && parts[2] == "members":
+			members := org.Members
+			switch {
+			case q.Get("filter") == "2fa_disabled":
+				members = org.No2FA
+			case q.Get("role") == "admin":
+				members = org.Admins
+			case q.Get("role") == "member":
+				members = without(org.Members, org.Admins)
+			}
+			return paginate(q, accounts(members))
+		case len(parts) == 3 && parts[2] == "outside_collaborators":
+			return paginate(q, accounts(org.OutsideCollaborators))
+		case len(parts) == 3 && parts[2] == "teams":
+			var teams []any
+			for _, t := range org.Teams {
+				teams = append(teams, map[string]any{"name": t.Name, "slug": t.Slug})
+			}
+			return paginate(q, teams)
+		case len(parts) == 5 && parts[2] == "teams":
+			for _, t := range org.Teams {
+				if t.Slug != parts[3] {
+					continue
+				}
+				switch parts[4] {
+				case "members":
+					return paginate(q, accounts(t.Members))
+				case "repos":
+					return paginate(q, teamRepos(parts[1], t.Repos))
+				}
+			}
+		}
+	}
+	return nil, notFound
+}
+
+// routeRepo answers /repos/{owner}/{repo}/..., parts is what follows the repository.
+func (s *Server) routeRepo(r *http.Request, repo string, parts []string) (*result, *apiError) {
+	if !s.repoExists(repo) {
+		return nil, notFound
+	}
+	raw := strings.Contains(r.Header.Get("Accept"), "raw")
+	releases := s.seed.Releases[repo]
+
+	switch {
+	case len(parts) == 1 && parts[0] == "readme":
+		md, ok := s.seed.Readmes[repo]
+		if !ok {
+			return nil, notFound
+		}
+		if raw {
+			return &result{raw: []byte(md), contentType: "text/plain; charset=utf-8"}, nil
+		}
+		return &result{body: map[string]any{
+			"name":     "README.md",
+			"path":     "README.md",
+			"size":     len(md),
+			"encoding": "base64",
+			"content":  wrap(base64.StdEncoding.EncodeToString([]byte(md)), 60),
+		}}, nil
+
+	case len(parts) == 1 && parts[0] == "releases":
+		return paginate(r.URL.Query(), releases)
+
+	case len(parts) == 2 && parts[0] == "releases" && parts[1] == "latest":
+		for _, rel := range releases {
+			if rel["draft"] != true && rel["prerelease"] != true {
+				return &result{body: rel}, nil
+			}
+		}
+
+	case len(parts) == 3 && parts[0] == "releases" && parts[1] == "tags":
+		for _, rel := range releases {
+			if rel["tag_name"] == parts[2] {
+				return &result{body: rel}, nil
+			}
+		}
+
+	case len(parts) == 3 && parts[0] == "releases" && parts[1] == "assets":
+		content, ok := s.assets[parts[2]]
+		if !ok {
+			return nil, notFound
+		}
+		if r.Header.Get("Accept") == "application/octet-stream" {
+			return &result{raw: content, contentType: "application/octet-stream"}, nil
+		}
+		for _, rel := range releases {
+			assets, _ := rel["assets"].([]any)
+			for _, a := range assets {
+				if a, ok := a.(map[string]any); ok && fmt.Sprint(a["id"]) == parts[2] {
+					return &result{body: a}, nil
+				}
+			}
+		}
+	}
+	return nil, notFound
+}
+
+func (s *Server) repoExists(full string) bool {
+	if _, ok := s.seed.Releases[full]; ok {
+		return true
+	}
+	if _, ok := s.seed.Readmes[full]; ok {
+		return true
+	}
+	owner, name, _ := strings.Cut(full, "/")
+	for _, r := range s.seed.Repos[owner] {
+		if r["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap breaks s every n bytes, like GitHub does with base64 content.
+func wrap(s string, n int) string {
+	var b strings.Builder
+	for len(s) > n {
+		b.WriteString(s[:n])
+		b.WriteByte('\n')
+		s = s[n:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// user adds the counters GitHub computes to a seeded user.
+func (s *Server) user(login string, u map[string]any) map[string]any {
+	out := make(map[string]any, len(u)+3)
+	out["public_repos"] = len(s.seed.Repos[login])
+	out["followers"] = len(s.seed.Followers[login])
+	out["type"] = "User"
+	if _, ok := s.seed.Orgs[login]; ok {
+		out["type"] = "Organization"
+	}
+	for k, v := range u { // seeded values win
+		out[k] = v
+	}
+	return out
+}
+
+// matchError returns the first error rule firing for r, or nil.
+func (s *Server) matchError(r *http.Request) *ErrorRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rule := range s.seed.Errors {
+		if rule.Method != "" && !strings.EqualFold(rule.Method, r.Method) {
+			continue
+		}
+		if p, ok := strings.CutSuffix(rule.Path, "*"); ok {
+			if !strings.HasPrefix(r.URL.Path, p) {
+				continue
+			}
+		} else if rule.Path != r.URL.Path {
+			continue
+		}
+		if rule.Count > 0 && rule.hits >= rule.Count {
+			continue
+		}
+		if rule.Probability > 0 && rand.Float64() >= rule.Probability {
+			continue
+		}
+		rule.hits++
+		return rule
+	}
+	return nil
+}
+
+// rateHeaders writes the X-RateLimit headers, consuming one request when count is set.
+// It reports false when the limit is exhausted.
+func (s *Server) rateHeaders(w http.ResponseWriter, count bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.After(s.reset) {
+		s.reset = now.Add(time.Hour).Truncate(time.Second)
+		s.remaining = s.limit
+	}
+	ok := true
+	if count {
+		if s.remaining == 0 {
+			ok = false
+		} else {
+			s.remaining--
+		}
+	}
+
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(s.limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(s.remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(s.reset.Unix(), 10))
+	h.Set("X-RateLimit-Resource", "core")
+	return ok
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":           msg,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+// paginate slices items according to the page and per_page parameters.
+func paginate[T any](q map[string][]string, items []T) (*result, *apiError) {
+	page, perPage := 1, 30
+	if v := first(q, "page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, &apiError{http.StatusUnprocessableEntity, "Invalid page"}
+		}
+		page = n
+	}
+	if v := first(q, "per_page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, &apiError{http.StatusUnprocessableEntity, "Invalid per_page"}
+		}
+		perPage = min(n, 100)
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	body := items[start:end]
+	if body == nil {
+		body = []T{} // [] rather than null
+	}
+	return &result{body: body, page: page, perPage: perPage, total: len(items)}, nil
+}
+
+func first(q map[string][]string, k string) string {
+	if v := q[k]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// linkHeader builds GitHub's RFC 5988 Link header for a page.
+func linkHeader(r *http.Request, page, perPage, total int) string {
+	last := (total + perPage - 1) / perPage
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	link := func(p int, rel string) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("per_page", strconv.Itoa(perPage))
+		return fmt.Sprintf(`<%s://%s%s?%s>; rel="%s"`, scheme, r.Host, r.URL.Path, q.Encode(), rel)
+	}
+
+	var links []string
+	if page < last {
+		links = append(links, link(page+1, "next"), link(last, "last"))
+	}
+	if page > 1 {
+		links = append(links, link(1, "first"), link(min(page-1, last), "prev"))
+	}
+	return strings.Join(links, ", ")
+}
+
+func accounts(logins []string) []any {
+	out := make([]any, len(logins))
+	for i, l := range logins {
+		out[i] = map[string]any{"login": l, "type": "User"}
+	}
+	return out
+}
+
+func teamRepos(org string, roles map[string]string) []any {
+	names := make([]string, 0, len(roles))
+	for n := range roles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	out := make([]any, len(names))
+	for i, n := range names {
+		out[i] = map[string]any{"name": n, "full_name": org + "/" + n, "role_name": roles[n]}
+	}
+	return out
+}
+
+// sortRepos orders repositories like GitHub does for the sort parameter,
+// full_name ascending by default.
+func sortRepos(repos []map[string]any, by, direction string) []map[string]any {
+	key := map[string]string{"created": "created_at", "updated": "updated_at", "pushed": "pushed_at"}[by]
+	if key == "" {
+		key = "name"
+	}
+	if direction == "" {
+		direction = "asc"
+		if key != "name" {
+			direction = "desc"
+		}
+	}
+
+	out := append([]map[string]any{}, repos...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, _ := out[i][key].(string) // dates are RFC 3339, they sort as strings
+		b, _ := out[j][key].(string)
+		if direction == "desc" {
+			return a > b
+		}
+		return a < b
+	})
+	return out
+}
+
+func without(all, remove []string) []string {
+	drop := make(map[string]bool, len(remove))
+	for _, r := range remove {
+		drop[r] = true
+	}
+	var out []string
+	for _, a := range all {
+		if !drop[a] {
+			out = append(out, a)
+		}
+	}
+	return out
+}
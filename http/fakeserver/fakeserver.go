@@ -0,0 +1,57 @@
+// fakeserver runs the fake GitHub API of http/fakegithub, for working offline
+// and without spending the real rate limit.
+//
+//	go run ./http/fakeserver -seed http/fakeserver/seed.json -addr :8081
+//	go run ./http -base-url http://localhost:8081
+//
+// See fakegithub.Seed for the seed file format.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"main/http/fakegithub"
+)
+
+func main() {
+	var (
+		seedPath = flag.String("seed", "http/fakeserver/seed.json", "seed `file`")
+		addr     = flag.String("addr", ":8081", "address to listen on")
+		quiet    = flag.Bool("q", false, "don't log requests")
+	)
+	flag.Parse()
+
+	seed, err := fakegithub.LoadSeed(*seedPath)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	var h http.Handler = fakegithub.New(seed)
+	if !*quiet {
+		h = logRequests(h)
+	}
+	log.Printf("serving %d users from %s on %s", len(seed.Users), *seedPath, *addr)
+	log.Fatal(http.ListenAndServe(*addr, h))
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func logRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		log.Printf("%s %s %d %v", r.Method, r.URL.RequestURI(), sw.status, time.Since(start).Round(time.Microsecond))
+	})
+}
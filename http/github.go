@@ -1,11 +1,19 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
-	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"main/http/github"
+	"main/http/recorder"
 )
 
 /*
@@ -17,76 +25,117 @@ This is replaced with an anonymous struct inside the getGithubInfo function
 		// but Go will still add it because of our tag
 		NumOfRepos int `json:"public_repos,omitempty"`
 	}
+
+The anonymous struct in turn moved to github.User once the client grew retries.
 */
-func main() {
 
-	n, nr, err := getGithubInfo("Jesserc")
-	if err != nil {
+// gh is shared by every call so repeated lookups are answered with a 304 from its cache.
+// 5xx, 429, secondary rate limits and timeouts are retried with exponential backoff,
+// see github.RetryPolicy
+var gh = github.NewClient()
+
+func main() {
+	if err := run(); err != nil {
 		log.Fatalf("error: %v\n", err)
 	}
-
-	fmt.Printf("Github details:\n Username: %v, Repository count: %v\n", n, nr)
-
 }
 
-func getGithubInfo(name string) (string, int, error) {
-	// PathEscape escapes the string so it can be safely placed inside a URL path segment, replacing special characters (including /) with %XX sequences as needed.
-	n := url.PathEscape(name)
-	url := fmt.Sprintf("https://api.github.com/users/%s", n)
-	fmt.Printf("url: %v\n", url)
-	resp, err := http.Get(url)
-	if err != nil {
-		// log.Fatalf("error %s", err)
-		/*
-			The code above is equal to
-			fmt.Printf("Error %v", err)
-			os.Exit(1)
-		*/
-		return "", 0, err
+// run does the work of main and returns its error instead of exiting, so the
+// deferred calls, saving a recording among them, always run.
+func run() error {
+	// go run . -record testdata/jesserc.json  talks to GitHub and saves the exchanges
+	// go run . -replay testdata/jesserc.json  runs offline from the saved file
+	record := flag.String("record", "", "record the GitHub responses to `file`")
+	replay := flag.String("replay", "", "serve the GitHub responses from `file` instead of the network")
+	metricsAddr := flag.String("metrics", "", "serve Prometheus metrics on `addr` (e.g. localhost:9090) and keep running")
+	verbose := flag.Bool("v", false, "log every request, not only the failed ones")
+	// go run ./http/fakeserver -seed http/fakeserver/seed.json, then -base-url http://localhost:8081
+	baseURL := flag.String("base-url", github.DefaultBaseURL, "GitHub API root")
+	flag.Parse()
+	gh.BaseURL = *baseURL
+
+	// the client logs structured events with a request ID instead of printing the URL
+	level := slog.LevelInfo
+	if *verbose {
+		level = slog.LevelDebug
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		// log.Fatalf("error: %s", resp.Status)
-		return "", 0, fmt.Errorf("%v - %s", url, resp.Status)
+	gh.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	metricsErr := make(chan error, 1)
+	if *metricsAddr != "" {
+		gh.Metrics = github.NewMetrics()
+		http.Handle("/metrics", gh.Metrics)
+		go func() {
+			metricsErr <- http.ListenAndServe(*metricsAddr, nil)
+		}()
 	}
 
-	// fmt.Printf("Content-Type: %s\n", resp.Header.Get("Content-Type"))
-
-	// This code will print the response body to the console in a non-formatted form
-	// if _, err := io.Copy(os.Stdout, resp.Body); err != nil {
-	// 	log.Fatalf("error: %s", err)
-	// }
-
-	/*
-		RELATING JSON TYPES TO GO TYPES
-
-		JSON <-> GO
-		string <-> string
-		null <-> nil
-		number <-> float64, however Go still have float32, int8, int16, int32, int64, int, uint8,...and so on
-		array <-> []any or []interfaces{}(old version). this is because arrays in Json can be mixed with different types so we use a generic type in terms of Go
-		object <-> map[string]any or struct
+	ct := github.NewCachingTransport(github.NewMemoryCache(10 << 20))
+	if *record != "" || *replay != "" {
+		mode, path := recorder.Record, *record
+		if *replay != "" {
+			mode, path = recorder.Replay, *replay
+		}
+		rec, err := recorder.New(path, mode)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := rec.Save(); err != nil {
+				log.Printf("error: %v", err)
+			}
+		}()
+		ct.Transport = rec
+	}
+	gh.HTTPClient = &http.Client{Transport: ct}
 
-		Go has the Time type but Json does not
+	n, nr, err := getGithubInfo("Jesserc")
+	// errors.Is/As let us branch on the kind of failure instead of parsing the message
+	var rl *github.RateLimitError
+	switch {
+	case errors.Is(err, github.ErrNotFound):
+		return fmt.Errorf("no such user: %w", err)
+	case errors.As(err, &rl):
+		return fmt.Errorf("rate limited until %v: %w", rl.Reset, err)
+	case err != nil:
+		return err
+	}
 
-	*/
+	fmt.Printf("Github details:\n Username: %v, Repository count: %v\n", n, nr)
 
-	// anonymous struct
-	// this is possible cause the struct isn't used outside this function
-	var r struct {
-		Name string `json:"name,omitempty"`
-		// this struct field name does not match a particular JSON field from our response
-		// but Go will still add it because of our tag
-		NumOfRepos int `json:"public_repos,omitempty"`
+	// ListRepos follows the Link header page by page, the callback returns false to stop early
+	ctx := context.Background()
+	opts := &github.ListReposOptions{Sort: "pushed", PerPage: 100}
+	gh.ListRepos(ctx, "Jesserc", opts)(func(r github.Repo, err error) bool {
+		if err != nil {
+			log.Printf("error: %v", err)
+			return false
+		}
+		fmt.Printf(" - %-30s %-12s stars: %d\n", r.Name, r.Language, r.Stars)
+		return true
+	})
+
+	if *metricsAddr != "" {
+		log.Printf("metrics on http://%s/metrics, Ctrl+C to stop", *metricsAddr)
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		select {
+		case <-ctx.Done():
+		case err := <-metricsErr:
+			return err
+		}
 	}
+	return nil
+}
 
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&r); err != nil {
-		// log.Fatalf("error: %s", err)
-		return "", 0, err
+func getGithubInfo(name string) (string, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
 
+	u, err := gh.User(ctx, name)
+	if err != nil {
+		return "", 0, err
 	}
-	defer resp.Body.Close()
 
-	return r.Name, r.NumOfRepos, nil
+	return u.Name, u.NumOfRepos, nil
 }
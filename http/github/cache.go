@@ -0,0 +1,318 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore holds raw HTTP responses by key.
+// MemoryCache and DiskCache are the two implementations.
+type CacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+}
+
+// CachingTransport is an http.RoundTripper that remembers GET responses
+// carrying an ETag or Last-Modified header and revalidates them with
+// If-None-Match/If-Modified-Since.
+// A 304 is answered from the cache, and GitHub does not count those against the rate limit.
+//
+//	c := github.NewClient()
+//	c.HTTPClient = &http.Client{Transport: github.NewCachingTransport(github.NewMemoryCache(10 << 20))}
+type CachingTransport struct {
+	// Transport sends the requests, http.DefaultTransport when nil.
+	Transport http.RoundTripper
+	Cache     CacheStore
+	// MaxBodyBytes is the largest body cached, like Client.MaxBodyBytes;
+	// larger responses are passed on uncached. 0 means no limit.
+	MaxBodyBytes int64
+}
+
+// NewCachingTransport returns a CachingTransport over http.DefaultTransport
+// caching bodies up to DefaultMaxBodyBytes.
+func NewCachingTransport(store CacheStore) *CachingTransport {
+	return &CachingTransport{Cache: store, MaxBodyBytes: DefaultMaxBodyBytes}
+}
+
+// FromCacheHeader is set on responses served from the cache.
+const FromCacheHeader = "X-From-Cache"
+
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return next.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.load(key, req)
+	if cached != nil {
+		// a RoundTripper must not modify the caller's request
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lm := cached.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		// keep the fresh rate limit headers and validators of the 304
+		for k, v := range resp.Header {
+			cached.Header[k] = v
+		}
+		cached.Header.Set(FromCacheHeader, "1")
+		return cached, nil
+	}
+
+	if resp.StatusCode != http.StatusOK ||
+		(resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+		return resp, nil
+	}
+
+	if !t.buffer(resp) {
+		return resp, nil
+	}
+	dump, err := httputil.DumpResponse(resp, true) // reads and replaces resp.Body
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	t.Cache.Set(key, dump)
+	return resp, nil
+}
+
+// buffer reads the body of resp in memory for DumpResponse, up to
+// t.MaxBodyBytes. It reports false when the body is larger, leaving resp
+// to be read as if nothing happened, so the limit of the client applies.
+func (t *CachingTransport) buffer(resp *http.Response) bool {
+	limit := t.MaxBodyBytes
+	if limit <= 0 {
+		return true
+	}
+	if resp.ContentLength > limit {
+		return false
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil || int64(len(b)) > limit {
+		// put back what was read, the error comes again from the body
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(b), resp.Body), resp.Body}
+		return false
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return true
+}
+
+// load returns the cached response for key, or nil.
+func (t *CachingTransport) load(key string, req *http.Request) *http.Response {
+	b, ok := t.Cache.Get(key)
+	if !ok {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), req)
+	if err != nil {
+		return nil
+	}
+	return resp
+}
+
+// cacheKey separates entries by URL, Accept and credentials,
+// since different tokens may see different data.
+func cacheKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.URL.String() + " " + req.Header.Get("Accept") + " " + hex.EncodeToString(auth[:8])
+}
+
+// lru tracks keys by recency and evicts the least recently used ones
+// once their total size exceeds max.
+type lru struct {
+	max, size int64
+	ll        *list.List // front is the most recently used
+	items     map[string]*list.Element
+}
+
+type lruEntry struct {
+	key  string
+	size int64
+}
+
+func newLRU(max int64) *lru {
+	return &lru{max: max, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (l *lru) touch(key string) bool {
+	e, ok := l.items[key]
+	if ok {
+		l.ll.MoveToFront(e)
+	}
+	return ok
+}
+
+// add records key with its size and returns the keys evicted to make room.
+func (l *lru) add(key string, size int64) (evicted []string) {
+	if e, ok := l.items[key]; ok {
+		l.size -= e.Value.(*lruEntry).size
+		l.ll.Remove(e)
+	}
+	l.items[key] = l.ll.PushFront(&lruEntry{key, size})
+	l.size += size
+
+	for l.size > l.max && l.ll.Len() > 0 {
+		e := l.ll.Back()
+		ent := e.Value.(*lruEntry)
+		l.ll.Remove(e)
+		delete(l.items, ent.key)
+		l.size -= ent.size
+		evicted = append(evicted, ent.key)
+	}
+	return evicted
+}
+
+// MemoryCache is an in-memory CacheStore bounded to a number of bytes.
+type MemoryCache struct {
+	mu   sync.Mutex
+	lru  *lru
+	data map[string][]byte
+}
+
+// NewMemoryCache returns a MemoryCache holding at most maxBytes of responses.
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{lru: newLRU(maxBytes), data: make(map[string][]byte)}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.lru.touch(key) {
+		return nil, false
+	}
+	return m.data[key], true
+}
+
+func (m *MemoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data[key] = value
+	for _, k := range m.lru.add(key, int64(len(value))) {
+		delete(m.data, k)
+	}
+}
+
+// DiskCache is a CacheStore keeping one file per response in a directory,
+// bounded to a number of bytes. Recency survives restarts through the file
+// modification times.
+type DiskCache struct {
+	dir string
+	mu  sync.Mutex
+	lru *lru
+}
+
+// NewDiskCache opens (creating it if needed) a cache directory holding at most maxBytes.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []os.FileInfo
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".tmp-") {
+			os.Remove(filepath.Join(dir, e.Name())) // left behind by a crash in Set
+			continue
+		}
+		if fi, err := e.Info(); err == nil && fi.Mode().IsRegular() {
+			infos = append(infos, fi)
+		}
+	}
+	// oldest first, so the newest end up at the front of the lru
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().Before(infos[j].ModTime()) })
+
+	d := &DiskCache{dir: dir, lru: newLRU(maxBytes)}
+	for _, fi := range infos {
+		for _, name := range d.lru.add(fi.Name(), fi.Size()) {
+			os.Remove(filepath.Join(dir, name))
+		}
+	}
+	return d, nil
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	name := d.name(key)
+	if !d.lru.touch(name) {
+		return nil, false
+	}
+	path := filepath.Join(d.dir, name)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return b, true
+}
+
+func (d *DiskCache) Set(key string, value []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	name := d.name(key)
+	// write then rename, so a crash never leaves a half written entry
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(value)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(d.dir, name))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	for _, old := range d.lru.add(name, int64(len(value))) {
+		os.Remove(filepath.Join(d.dir, old))
+	}
+}
+
+func (d *DiskCache) name(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"main/http/fakegithub"
+)
+
+// TestCachingTransportFake checks that a repeated request is revalidated
+// with the ETag of the fake and answered from the cache.
+func TestCachingTransportFake(t *testing.T) {
+	c, requests := newFake(t, &fakegithub.Seed{Users: []map[string]any{{"login": "alice", "name": "Alice"}}})
+	cache := NewMemoryCache(1 << 20)
+	var notModified int
+	next := c.HTTPClient.Transport
+	c.HTTPClient = &http.Client{Transport: &CachingTransport{Transport: transportFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusNotModified {
+			notModified++
+		}
+		return resp, err
+	}), Cache: cache}}
+
+	for i := 0; i < 3; i++ {
+		u, err := c.User(context.Background(), "alice")
+		if err != nil || u.Name != "Alice" {
+			t.Fatalf("request %d: %+v, %v", i+1, u, err)
+		}
+	}
+	if requests.Load() != 3 || notModified != 2 {
+		t.Errorf("%d requests, %d not modified, want 3 and 2", requests.Load(), notModified)
+	}
+	if n := cache.lru.ll.Len(); n != 1 {
+		t.Errorf("%d cached responses, want 1", n)
+	}
+}
+
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (f transportFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// revalidating serves body with the validator header, or a 304 when the
+// request carries it back in cond, with one less remaining request each time.
+func revalidating(t *testing.T, header, value, cond, body string) (*http.Client, string) {
+	var n atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := n.Add(1)
+		w.Header().Set(header, value)
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(100-count))
+		if r.Header.Get(cond) == value {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return &http.Client{Transport: NewCachingTransport(NewMemoryCache(1 << 20))}, srv.URL
+}
+
+func TestCachingTransportRevalidation(t *testing.T) {
+	for _, c := range []struct{ header, value, cond string }{
+		{"ETag", `"v1"`, "If-None-Match"},
+		{"Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT", "If-Modified-Since"},
+	} {
+		client, url := revalidating(t, c.header, c.value, c.cond, "hello")
+		for i, want := range []struct{ fromCache, remaining string }{{"", "99"}, {"1", "98"}, {"1", "97"}} {
+			resp, err := client.Get(url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || string(b) != "hello" {
+				t.Errorf("%s request %d: %d %q", c.header, i+1, resp.StatusCode, b)
+			}
+			// the headers of the 304 replace the stored ones
+			if got := resp.Header.Get(FromCacheHeader); got != want.fromCache {
+				t.Errorf("%s request %d: %s %q, want %q", c.header, i+1, FromCacheHeader, got, want.fromCache)
+			}
+			if got := resp.Header.Get("X-RateLimit-Remaining"); got != want.remaining {
+				t.Errorf("%s request %d: remaining %s, want %s", c.header, i+1, got, want.remaining)
+			}
+		}
+	}
+}
+
+func TestCachingTransportMaxBodyBytes(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"big"`)
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	cache := NewMemoryCache(1 << 20)
+	for _, limit := range []int64{10, 100} {
+		client := &http.Client{Transport: &CachingTransport{Cache: cache, MaxBodyBytes: limit}}
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(b) != body {
+			t.Errorf("limit %d: read %d bytes, want the whole body", limit, len(b))
+		}
+		if cached := cache.lru.ll.Len() == 1; cached != (limit == 100) {
+			t.Errorf("limit %d: cached %v", limit, cached)
+		}
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	m := NewMemoryCache(10)
+	m.Set("a", []byte("aaaa"))
+	m.Set("b", []byte("bbbb"))
+	m.Get("a") // b is now the least recently used
+	m.Set("c", []byte("cccc"))
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := m.Get(key); ok != want {
+			t.Errorf("%s cached %v, want %v", key, ok, want)
+		}
+	}
+
+	m.Set("a", []byte("aaaaaaaa")) // replacing counts the new size only
+	if m.lru.size != 8 || len(m.data) != 1 {
+		t.Errorf("size %d with %d entries, want 8 with a only", m.lru.size, len(m.data))
+	}
+	m.Set("huge", make([]byte, 11)) // larger than the cache, evicts everything
+	if m.lru.size != 0 || len(m.data) != 0 {
+		t.Errorf("size %d with %d entries after a huge one, want empty", m.lru.size, len(m.data))
+	}
+}
+
+// TestDiskCacheReload checks that a reopened cache evicts by the recency
+// of the files, not their names or the order of the directory.
+func TestDiskCacheReload(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDiskCache(dir, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{"old", "newest", "middle"}
+	age := map[string]time.Duration{"old": 3 * time.Hour, "newest": time.Hour, "middle": 2 * time.Hour}
+	for _, k := range keys {
+		d.Set(k, make([]byte, 10))
+		mtime := time.Now().Add(-age[k])
+		if err := os.Chtimes(filepath.Join(dir, d.name(k)), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte("half written"), 0o644)
+
+	d, err = NewDiskCache(dir, 25) // room for two, old goes
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".tmp-123")); !os.IsNotExist(err) {
+		t.Errorf("temporary file left: %v", err)
+	}
+	d.Set("new", make([]byte, 10)) // middle goes
+	for key, want := range map[string]bool{"old": false, "middle": false, "newest": true, "new": true} {
+		_, ok := d.Get(key)
+		_, err := os.Stat(filepath.Join(dir, d.name(key)))
+		if ok != want || (err == nil) != want {
+			t.Errorf("%s cached %v, on disk %v, want %v", key, ok, err == nil, want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors, match them with errors.Is:
+//
+//	if errors.Is(err, github.ErrNotFound) { ... }
+//
+// Use errors.As with the matching *XxxError type to get the details.
+var (
+	ErrNotFound     = errors.New("github: not found")
+	ErrUnauthorized = errors.New("github: unauthorized")
+	ErrForbidden    = errors.New("github: forbidden")
+	ErrRateLimited  = errors.New("github: rate limited")
+	ErrValidation   = errors.New("github: validation failed")
+	ErrServer       = errors.New("github: server error")
+)
+
+// APIError is GitHub's JSON error body plus the request that caused it.
+// Every typed error below wraps one, so errors.As(err, &apiErr) always works.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Method     string `json:"-"`
+	URL        string `json:"-"`
+
+	Message          string       `json:"message"`
+	DocumentationURL string       `json:"documentation_url,omitempty"`
+	Errors           []FieldError `json:"errors,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: %d", e.Method, e.URL, e.StatusCode)
+	if e.Message != "" {
+		fmt.Fprintf(&b, " %s", e.Message)
+	}
+	for _, fe := range e.Errors {
+		fmt.Fprintf(&b, "; %s", fe)
+	}
+	return b.String()
+}
+
+// FieldError is one entry of the "errors" array of a 422 response.
+type FieldError struct {
+	Resource string `json:"resource,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// UnmarshalJSON accepts both the object form and the bare strings GitHub sometimes sends.
+func (fe *FieldError) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*fe = FieldError{Message: s}
+		return nil
+	}
+
+	type plain FieldError // no UnmarshalJSON method, avoids recursion
+	return json.Unmarshal(data, (*plain)(fe))
+}
+
+func (fe FieldError) String() string {
+	if fe.Message != "" && fe.Field == "" {
+		return fe.Message
+	}
+	s := fmt.Sprintf("%s.%s %s", fe.Resource, fe.Field, fe.Code)
+	if fe.Message != "" {
+		s += ": " + fe.Message
+	}
+	return s
+}
+
+// NotFoundError is returned for 404.
+type NotFoundError struct{ *APIError }
+
+func (e *NotFoundError) Unwrap() error        { return e.APIError }
+func (e *NotFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// UnauthorizedError is returned for 401, usually a missing or bad token.
+type UnauthorizedError struct{ *APIError }
+
+func (e *UnauthorizedError) Unwrap() error        { return e.APIError }
+func (e *UnauthorizedError) Is(target error) bool { return target == ErrUnauthorized }
+
+// ForbiddenError is returned for a 403 that is not a rate limit.
+type ForbiddenError struct{ *APIError }
+
+func (e *ForbiddenError) Unwrap() error        { return e.APIError }
+func (e *ForbiddenError) Is(target error) bool { return target == ErrForbidden }
+
+// RateLimitError is returned for 429, and for 403 when the primary or
+// secondary rate limit is hit.
+type RateLimitError struct {
+	*APIError
+	Limit     int
+	Remaining int
+	Reset     time.Time
+	// RetryAfter is set from the Retry-After header, used by secondary limits.
+	RetryAfter time.Duration
+	// Secondary is true for abuse/secondary rate limits.
+	Secondary bool
+}
+
+func (e *RateLimitError) Unwrap() error        { return e.APIError }
+func (e *RateLimitError) Is(target error) bool { return target == ErrRateLimited }
+
+// ValidationError is returned for 422, see APIError.Errors for the details.
+type ValidationError struct{ *APIError }
+
+func (e *ValidationError) Unwrap() error        { return e.APIError }
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// ServerError is returned for 5xx.
+type ServerError struct{ *APIError }
+
+func (e *ServerError) Unwrap() error        { return e.APIError }
+func (e *ServerError) Is(target error) bool { return target == ErrServer }
+
+// checkResponse turns a non-2xx response into one of the typed errors above
+// and closes its body.
+func checkResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	e := &APIError{StatusCode: resp.StatusCode}
+	if req := resp.Request; req != nil {
+		e.Method, e.URL = req.Method, req.URL.String()
+	}
+
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(b, e); err != nil {
+		// not JSON (e.g. an HTML page from a proxy), keep what we can
+		e.Message = strings.TrimSpace(string(b))
+		if e.Message == "" || len(e.Message) > 200 {
+			e.Message = http.StatusText(resp.StatusCode)
+		}
+	}
+
+	switch code := resp.StatusCode; {
+	case code == http.StatusNotFound:
+		return &NotFoundError{e}
+	case code == http.StatusUnauthorized:
+		return &UnauthorizedError{e}
+	case code == http.StatusTooManyRequests,
+		code == http.StatusForbidden && isRateLimited(resp, e):
+		return newRateLimitError(resp, e)
+	case code == http.StatusForbidden:
+		return &ForbiddenError{e}
+	case code == http.StatusUnprocessableEntity:
+		return &ValidationError{e}
+	case code >= 500:
+		return &ServerError{e}
+	}
+	return e
+}
+
+func isRateLimited(resp *http.Response, e *APIError) bool {
+	return resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+		resp.Header.Get("Retry-After") != "" ||
+		strings.Contains(strings.ToLower(e.Message), "rate limit")
+}
+
+func newRateLimitError(resp *http.Response, e *APIError) *RateLimitError {
+	rl := &RateLimitError{
+		APIError:  e,
+		Secondary: strings.Contains(strings.ToLower(e.Message), "secondary rate limit"),
+	}
+	rl.Limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	rl.Remaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if s, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(s, 0)
+	}
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		rl.RetryAfter = time.Duration(s) * time.Second
+	}
+	return rl
+}
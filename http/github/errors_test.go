@@ -0,0 +1,151 @@
+package github
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// response is a GitHub answer to a POST of /repos/acme/app/issues.
+func response(status int, header map[string]string, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    httptest.NewRequest(http.MethodPost, "https://api.github.com/repos/acme/app/issues", nil),
+	}
+	for k, v := range header {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestCheckResponse(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		resp     *http.Response
+		sentinel error
+		message  string
+		text     string // of Error, after "POST https://api.github.com/repos/acme/app/issues: "
+	}{
+		{
+			"not found",
+			response(404, nil, `{"message": "Not Found", "documentation_url": "https://docs.github.com/rest/issues/issues#create-an-issue", "status": "404"}`),
+			ErrNotFound, "Not Found", "404 Not Found",
+		},
+		{
+			"bad credentials",
+			response(401, nil, `{"message": "Bad credentials", "documentation_url": "https://docs.github.com/rest", "status": "401"}`),
+			ErrUnauthorized, "Bad credentials", "401 Bad credentials",
+		},
+		{
+			"forbidden",
+			response(403, map[string]string{"X-RateLimit-Remaining": "4999"}, `{"message": "Resource not accessible by integration", "documentation_url": "https://docs.github.com/rest/issues/issues#create-an-issue"}`),
+			ErrForbidden, "Resource not accessible by integration", "403 Resource not accessible by integration",
+		},
+		{
+			"primary rate limit",
+			response(403, map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000000"},
+				`{"message": "API rate limit exceeded for 192.0.2.1. (But here's the good news: Authenticated requests get a higher rate limit. Check out the documentation for more details.)", "documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#rate-limiting"}`),
+			ErrRateLimited, "", "",
+		},
+		{
+			"secondary rate limit",
+			response(403, map[string]string{"Retry-After": "60"}, `{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.", "documentation_url": "https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`),
+			ErrRateLimited, "", "",
+		},
+		{
+			"too many requests",
+			response(429, map[string]string{"Retry-After": "5"}, `{"message": "Too Many Requests"}`),
+			ErrRateLimited, "Too Many Requests", "429 Too Many Requests",
+		},
+		{
+			"missing field",
+			response(422, nil, `{"message": "Validation Failed", "errors": [{"resource": "Issue", "code": "missing_field", "field": "title"}], "documentation_url": "https://docs.github.com/rest/issues/issues#create-an-issue"}`),
+			ErrValidation, "Validation Failed", "422 Validation Failed; Issue.title missing_field",
+		},
+		{
+			"custom validation",
+			response(422, nil, `{"message": "Validation Failed", "errors": [{"resource": "PullRequest", "code": "custom", "message": "A pull request already exists for acme:fix."}]}`),
+			ErrValidation, "Validation Failed", "422 Validation Failed; A pull request already exists for acme:fix.",
+		},
+		{
+			"bare string errors",
+			response(422, nil, `{"message": "Validation Failed", "errors": ["Could not resolve to a node with the global id of 'x'", "title is too long (maximum is 256 characters)"]}`),
+			ErrValidation, "Validation Failed",
+			"422 Validation Failed; Could not resolve to a node with the global id of 'x'; title is too long (maximum is 256 characters)",
+		},
+		{
+			"proxy error page",
+			response(502, nil, "<html><body><h1>502 Bad Gateway</h1>"+strings.Repeat(" ", 200)+"</body></html>"),
+			ErrServer, "Bad Gateway", "502 Bad Gateway",
+		},
+		{
+			"short text",
+			response(503, nil, "upstream connect error\n"),
+			ErrServer, "upstream connect error", "503 upstream connect error",
+		},
+		{
+			"other status",
+			response(409, nil, `{"message": "Git Repository is empty."}`),
+			nil, "Git Repository is empty.", "409 Git Repository is empty.",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkResponse(c.resp)
+			for _, s := range []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrRateLimited, ErrValidation, ErrServer} {
+				if got := errors.Is(err, s); got != (s == c.sentinel) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, s, got)
+				}
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("%T isn't an *APIError", err)
+			}
+			if apiErr.StatusCode != c.resp.StatusCode || apiErr.Method != http.MethodPost || apiErr.URL != "https://api.github.com/repos/acme/app/issues" {
+				t.Errorf("got %d %s %s", apiErr.StatusCode, apiErr.Method, apiErr.URL)
+			}
+			if c.message != "" && apiErr.Message != c.message {
+				t.Errorf("message %q, want %q", apiErr.Message, c.message)
+			}
+			if want := "POST https://api.github.com/repos/acme/app/issues: " + c.text; c.text != "" && err.Error() != want {
+				t.Errorf("error %q, want %q", err, want)
+			}
+		})
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		resp *http.Response
+		want RateLimitError
+	}{
+		{
+			"primary",
+			response(403, map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000000"},
+				`{"message": "API rate limit exceeded for 192.0.2.1."}`),
+			RateLimitError{Limit: 60, Remaining: 0, Reset: time.Unix(1700000000, 0)},
+		},
+		{
+			"secondary",
+			response(403, map[string]string{"Retry-After": "60", "X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "4990"},
+				`{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`),
+			RateLimitError{Limit: 5000, Remaining: 4990, RetryAfter: time.Minute, Secondary: true},
+		},
+	} {
+		var rl *RateLimitError
+		if err := checkResponse(c.resp); !errors.As(err, &rl) {
+			t.Errorf("%s: %T isn't a *RateLimitError", c.name, err)
+			continue
+		}
+		if rl.Limit != c.want.Limit || rl.Remaining != c.want.Remaining || !rl.Reset.Equal(c.want.Reset) ||
+			rl.RetryAfter != c.want.RetryAfter || rl.Secondary != c.want.Secondary {
+			t.Errorf("%s: got %+v, want %+v", c.name, *rl, c.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"main/http/fakegithub"
+)
+
+// newFake runs a fake GitHub for seed over TLS, and returns a client of it
+// and the number of requests it served.
+func newFake(t *testing.T, seed *fakegithub.Seed) (*Client, *atomic.Int64) {
+	var n atomic.Int64
+	fake := fakegithub.New(seed)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.Add(1)
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.BaseURL = srv.URL
+	c.HTTPClient = srv.Client()
+	return c, &n
+}
+
+func TestFakeRepoPagination(t *testing.T) {
+	var repos []map[string]any
+	for i := 0; i < 250; i++ {
+		repos = append(repos, map[string]any{"name": fmt.Sprintf("r%03d", i), "stargazers_count": i})
+	}
+	c, requests := newFake(t, &fakegithub.Seed{
+		Users: []map[string]any{{"login": "alice", "name": "Alice"}},
+		Repos: map[string][]map[string]any{"alice": repos},
+	})
+
+	got, err := c.CollectRepos(context.Background(), "alice", &ListReposOptions{PerPage: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 250 || got[249].Name != "r249" || got[249].Stars != 249 {
+		t.Fatalf("got %d repos, want 250 through r249", len(got))
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("%d requests, want 3 pages", n)
+	}
+
+	// stopping early doesn't fetch the next pages
+	requests.Store(0)
+	seen := 0
+	c.ListRepos(context.Background(), "alice", &ListReposOptions{PerPage: 10})(func(r Repo, err error) bool {
+		seen++
+		return seen < 15
+	})
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests for 15 repos, want 2 pages of 10", n)
+	}
+}
+
+func TestFakeUser(t *testing.T) {
+	c, _ := newFake(t, &fakegithub.Seed{
+		Users: []map[string]any{{"login": "alice", "name": "Alice"}},
+	})
+	u, err := c.User(context.Background(), "alice")
+	if err != nil || u.Login != "alice" || u.Name != "Alice" {
+		t.Fatalf("got %+v, %v", u, err)
+	}
+
+	_, err = c.User(context.Background(), "nobody")
+	var nf *NotFoundError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &nf) {
+		t.Errorf("got %v, want a *NotFoundError", err)
+	}
+}
@@ -0,0 +1,277 @@
+// Package github is a small client for the GitHub REST API.
+// It grew out of the getGithubInfo demo in http/github.go.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the root of the public GitHub REST API.
+const DefaultBaseURL = "https://api.github.com"
+
+// Client talks to the GitHub REST API.
+// The zero value is not usable, create one with NewClient.
+type Client struct {
+	// BaseURL is the API root, point it at an httptest server in tests.
+	BaseURL string
+	// Token is sent as a bearer token when set, raising the rate limit from 60 to 5000 requests an hour.
+	Token string
+	// HTTPClient sends the requests, http.DefaultClient when nil.
+	HTTPClient *http.Client
+	// Retry decides how transient failures are retried.
+	Retry RetryPolicy
+	// Limiter, when set, is waited on before every attempt.
+	// Share one between clients to share the rate limit.
+	Limiter *Limiter
+	// Logger receives a structured event per attempt, slog.Default() when nil.
+	// Successful attempts are logged at debug level, failed ones at warn.
+	Logger *slog.Logger
+	// Metrics, when set, records the latency, status and rate limit of every attempt.
+	Metrics *Metrics
+	// MaxBodyBytes caps the size of a response body, 0 means no limit.
+	// Reading past it fails with an *http.MaxBytesError.
+	MaxBodyBytes int64
+}
+
+// DefaultMaxBodyBytes is the response size limit set by NewClient.
+const DefaultMaxBodyBytes = 32 << 20
+
+// NewClient returns a Client for api.github.com with the default retry policy.
+func NewClient() *Client {
+	return &Client{
+		BaseURL:      DefaultBaseURL,
+		Retry:        DefaultRetryPolicy,
+		MaxBodyBytes: DefaultMaxBodyBytes,
+	}
+}
+
+// User is the subset of the GitHub user object we care about.
+/*
+	RELATING JSON TYPES TO GO TYPES
+
+	JSON <-> GO
+	string <-> string
+	null <-> nil
+	number <-> float64, however Go still have float32, int8, int16, int32, int64, int, uint8,...and so on
+	array <-> []any or []interfaces{}(old version). this is because arrays in Json can be mixed with different types so we use a generic type in terms of Go
+	object <-> map[string]any or struct
+
+	Go has the Time type but Json does not
+*/
+type User struct {
+	Login string `json:"login"`
+	Name  string `json:"name,omitempty"`
+	// this struct field name does not match a particular JSON field from our response
+	// but Go will still add it because of our tag
+	NumOfRepos int       `json:"public_repos,omitempty"`
+	Followers  int       `json:"followers"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// User fetches the public profile of name.
+func (c *Client) User(ctx context.Context, name string) (*User, error) {
+	// PathEscape escapes the string so it can be safely placed inside a URL path segment,
+	// replacing special characters (including /) with %XX sequences as needed.
+	req, err := c.NewRequest(ctx, http.MethodGet, "users/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var u User
+	if err := c.Do(req, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// ListFollowers iterates over the followers of user, see ListRepos for how to call the iterator.
+func (c *Client) ListFollowers(ctx context.Context, user string) func(yield func(Account, error) bool) {
+	return paginate[Account](ctx, c, "users/"+url.PathEscape(user)+"/followers", url.Values{"per_page": {"100"}})
+}
+
+// NewRequest builds a request for path, which is relative to c.BaseURL.
+// Absolute URLs, such as the ones in Link headers, are used as is.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	u := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		u = strings.TrimSuffix(c.BaseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return req, nil
+}
+
+// Do sends req, retrying transient failures, and decodes a 2xx JSON body into v.
+// v may be nil when the body is not needed.
+func (c *Client) Do(req *http.Request, v any) error {
+	_, err := c.doJSON(req, v)
+	return err
+}
+
+// doJSON is Do but also returns the response, whose body is already closed,
+// for callers that need the headers.
+func (c *Client) doJSON(req *http.Request, v any) (*http.Response, error) {
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body := c.limitBody(resp)
+	if v == nil {
+		_, err = io.Copy(io.Discard, body)
+	} else {
+		err = json.NewDecoder(body).Decode(v)
+	}
+	return resp, bodyErr(req, err)
+}
+
+// limitBody wraps resp.Body with http.MaxBytesReader when c.MaxBodyBytes is set.
+func (c *Client) limitBody(resp *http.Response) io.Reader {
+	if c.MaxBodyBytes <= 0 {
+		return resp.Body
+	}
+	return http.MaxBytesReader(nil, resp.Body, c.MaxBodyBytes)
+}
+
+// bodyErr explains the error of a body cut by limitBody, which otherwise reads
+// "request body too large" because MaxBytesReader was made for servers.
+func bodyErr(req *http.Request, err error) error {
+	var mb *http.MaxBytesError
+	if errors.As(err, &mb) {
+		return fmt.Errorf("github: %s %s: response body exceeds %d bytes: %w", req.Method, req.URL, mb.Limit, err)
+	}
+	return err
+}
+
+// do sends req according to c.Retry and returns the first successful response.
+// The caller must close the response body.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	p := c.Retry
+	attempts := p.MaxAttempts
+	if attempts < 1 || !isIdempotent(req) {
+		attempts = 1
+	}
+
+	// work on a copy, the caller's request and its headers are left as they are
+	ctx, id := req.Context(), RequestID(req.Context())
+	if id == "" {
+		id = newRequestID()
+		ctx = WithRequestID(ctx, id)
+	}
+	req = req.Clone(ctx)
+	req.Header.Set(RequestIDHeader, id)
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, err := c.httpClient().Do(req)
+		elapsed := time.Since(start)
+		c.Limiter.observe(resp)
+		c.Metrics.observe(req.Method, resp, elapsed)
+
+		attrs := attemptAttrs(req, resp, err, attempt, elapsed)
+		reason, hint := retryReason(resp, err)
+		if reason == "" {
+			level := slog.LevelDebug
+			if err != nil || resp.StatusCode >= 400 {
+				level = slog.LevelWarn
+			}
+			c.logger().LogAttrs(req.Context(), level, "github request", attrs...)
+
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode >= 300 {
+				return nil, checkResponse(resp)
+			}
+			return resp, nil
+		}
+
+		attrs = append(attrs, slog.String("reason", reason))
+		wait := p.backoff(attempt, hint)
+		if attempt >= attempts || (p.MaxDelay > 0 && hint > p.MaxDelay) {
+			c.logger().LogAttrs(req.Context(), slog.LevelError, "github request failed, giving up", attrs...)
+			if err != nil {
+				return nil, err
+			}
+			return nil, checkResponse(resp)
+		}
+		attrs = append(attrs, slog.Duration("retry_in", wait))
+		c.logger().LogAttrs(req.Context(), slog.LevelWarn, "github request failed, retrying", attrs...)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attemptAttrs describes one attempt for the structured log.
+func attemptAttrs(req *http.Request, resp *http.Response, err error, attempt int, elapsed time.Duration) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("request_id", RequestID(req.Context())),
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", elapsed),
+	}
+	if err != nil {
+		return append(attrs, slog.String("error", err.Error()))
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if v := resp.Header.Get("X-RateLimit-Remaining"); v != "" {
+		attrs = append(attrs, slog.String("rate_limit_remaining", v))
+	}
+	if v := resp.Header.Get("X-GitHub-Request-Id"); v != "" {
+		attrs = append(attrs, slog.String("github_request_id", v))
+	}
+	if resp.Header.Get(FromCacheHeader) != "" {
+		attrs = append(attrs, slog.Bool("from_cache", true))
+	}
+	return attrs
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,325 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GraphQLError is one entry of the "errors" array of a GraphQL response.
+type GraphQLError struct {
+	Message   string `json:"message"`
+	Type      string `json:"type,omitempty"`
+	Path      []any  `json:"path,omitempty"`
+	Locations []struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"locations,omitempty"`
+}
+
+func (e GraphQLError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("%s: %s", e.Type, e.Message)
+	}
+	return e.Message
+}
+
+// GraphQLErrors is returned by GraphQL when the response has errors.
+// GraphQL can answer with both data and errors, in which case the data
+// has still been decoded.
+type GraphQLErrors []GraphQLError
+
+func (es GraphQLErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return "github: graphql: " + strings.Join(msgs, "; ")
+}
+
+// Is matches ErrNotFound when GitHub could not resolve an object of the query,
+// e.g. an unknown login.
+func (es GraphQLErrors) Is(target error) bool {
+	if target != ErrNotFound {
+		return false
+	}
+	for _, e := range es {
+		if e.Type == "NOT_FOUND" {
+			return true
+		}
+	}
+	return false
+}
+
+// PageInfo is the Relay connection cursor GitHub puts on every paginated field.
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// GraphQL posts query with its variables to the v4 API and decodes the
+// "data" member of the response into data.
+// Queries are retried like GET requests, mutations are never retried.
+func (c *Client) GraphQL(ctx context.Context, query string, vars map[string]any, data any) error {
+	body, err := json.Marshal(struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables,omitempty"`
+	}{query, vars})
+	if err != nil {
+		return err
+	}
+
+	if !isMutation(query) {
+		ctx = withIdempotent(ctx)
+	}
+	req, err := c.NewRequest(ctx, http.MethodPost, "graphql", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if err := c.Do(req, &resp); err != nil {
+		return err
+	}
+
+	if data != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			return err
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+	return nil
+}
+
+// isMutation reports whether the GraphQL document query has a mutation. It
+// reads the keyword starting each top-level definition, skipping comments,
+// strings and the bodies of the definitions, so a mutation after a comment or
+// a fragment definition is found and a "mutation" in a string is not.
+func isMutation(query string) bool {
+	depth, start := 0, true
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			i = skipString(query, i)
+		case c == '{' || c == '(' || c == '[':
+			depth++
+			start = false // a { at the top is the shorthand of a query
+		case c == '}' || c == ')' || c == ']':
+			depth--
+			start = depth == 0 && c == '}'
+		case depth == 0 && start && isNameStart(c):
+			j := i
+			for j < len(query) && (isNameStart(query[j]) || '0' <= query[j] && query[j] <= '9') {
+				j++
+			}
+			if query[i:j] == "mutation" {
+				return true
+			}
+			start, i = false, j-1
+		}
+	}
+	return false
+}
+
+// skipString returns the index of the quote closing the string or block
+// string opening at query[i].
+func skipString(query string, i int) int {
+	if strings.HasPrefix(query[i:], `"""`) {
+		for j := i + 3; j < len(query); j++ {
+			if query[j] == '\\' {
+				j++ // \""" doesn't close a block string
+			} else if strings.HasPrefix(query[j:], `"""`) {
+				return j + 2
+			}
+		}
+		return len(query)
+	}
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			j++
+		case '"', '\n':
+			return j
+		}
+	}
+	return len(query)
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// GraphQLPaginate runs query once per page. The query must take an $after: String
+// variable and pass it to the paginated field. page decodes each "data" member
+// and returns the field's pageInfo; GraphQLPaginate stops when there is no next page
+// or page returns an error.
+func (c *Client) GraphQLPaginate(ctx context.Context, query string, vars map[string]any, page func(data json.RawMessage) (PageInfo, error)) error {
+	v := make(map[string]any, len(vars)+1)
+	for k, val := range vars {
+		v[k] = val
+	}
+
+	for {
+		var data json.RawMessage
+		if err := c.GraphQL(ctx, query, v, &data); err != nil {
+			return err
+		}
+		pi, err := page(data)
+		if err != nil {
+			return err
+		}
+		if !pi.HasNextPage || pi.EndCursor == "" {
+			return nil
+		}
+		v["after"] = pi.EndCursor
+	}
+}
+
+// Profile is a user with their most starred repositories, fetched in one GraphQL call.
+type Profile struct {
+	Login     string
+	Name      string
+	Followers int
+	Repos     int
+	// Contributions is the number of contributions of the last year.
+	Contributions int
+	TopRepos      []Repo
+}
+
+// graphqlRepo is how the v4 API spells the fields of Repo.
+type graphqlRepo struct {
+	Name            string    `json:"name"`
+	NameWithOwner   string    `json:"nameWithOwner"`
+	StargazerCount  int       `json:"stargazerCount"`
+	ForkCount       int       `json:"forkCount"`
+	IsArchived      bool      `json:"isArchived"`
+	PushedAt        time.Time `json:"pushedAt"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+}
+
+func (r graphqlRepo) repo() Repo {
+	out := Repo{
+		Name:     r.Name,
+		FullName: r.NameWithOwner,
+		Stars:    r.StargazerCount,
+		Forks:    r.ForkCount,
+		Archived: r.IsArchived,
+		PushedAt: r.PushedAt,
+	}
+	if r.PrimaryLanguage != nil {
+		out.Language = r.PrimaryLanguage.Name
+	}
+	return out
+}
+
+const repoFields = `name nameWithOwner stargazerCount forkCount isArchived pushedAt primaryLanguage { name }`
+
+const profileQuery = `query($login: String!, $n: Int!) {
+  user(login: $login) {
+    login
+    name
+    followers { totalCount }
+    repositories(first: $n, ownerAffiliations: OWNER, privacy: PUBLIC, orderBy: {field: STARGAZERS, direction: DESC}) {
+      totalCount
+      nodes { ` + repoFields + ` }
+    }
+    contributionsCollection { contributionCalendar { totalContributions } }
+  }
+}`
+
+// Profile fetches login's profile and their top n public repositories by stars
+// in a single round trip. n is capped at 100 by GitHub. It needs a Token.
+func (c *Client) Profile(ctx context.Context, login string, n int) (*Profile, error) {
+	var data struct {
+		User *struct {
+			Login     string `json:"login"`
+			Name      string `json:"name"`
+			Followers struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"followers"`
+			Repositories struct {
+				TotalCount int           `json:"totalCount"`
+				Nodes      []graphqlRepo `json:"nodes"`
+			} `json:"repositories"`
+			ContributionsCollection struct {
+				ContributionCalendar struct {
+					TotalContributions int `json:"totalContributions"`
+				} `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+	if err := c.GraphQL(ctx, profileQuery, map[string]any{"login": login, "n": n}, &data); err != nil {
+		return nil, err
+	}
+	if data.User == nil {
+		return nil, fmt.Errorf("github: graphql: user %q: %w", login, ErrNotFound)
+	}
+
+	u := data.User
+	p := &Profile{
+		Login:         u.Login,
+		Name:          u.Name,
+		Followers:     u.Followers.TotalCount,
+		Repos:         u.Repositories.TotalCount,
+		Contributions: u.ContributionsCollection.ContributionCalendar.TotalContributions,
+	}
+	for _, r := range u.Repositories.Nodes {
+		p.TopRepos = append(p.TopRepos, r.repo())
+	}
+	return p, nil
+}
+
+const starsQuery = `query($login: String!, $after: String) {
+  user(login: $login) {
+    repositories(first: 100, after: $after, ownerAffiliations: OWNER, privacy: PUBLIC) {
+      pageInfo { hasNextPage endCursor }
+      nodes { stargazerCount }
+    }
+  }
+}`
+
+// TotalStars sums the stars of every public repository owned by login,
+// walking the repositories connection 100 at a time.
+func (c *Client) TotalStars(ctx context.Context, login string) (int, error) {
+	total := 0
+	err := c.GraphQLPaginate(ctx, starsQuery, map[string]any{"login": login}, func(raw json.RawMessage) (PageInfo, error) {
+		var data struct {
+			User *struct {
+				Repositories struct {
+					PageInfo PageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						StargazerCount int `json:"stargazerCount"`
+					} `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return PageInfo{}, err
+		}
+		if data.User == nil {
+			return PageInfo{}, fmt.Errorf("github: graphql: user %q: %w", login, ErrNotFound)
+		}
+
+		for _, n := range data.User.Repositories.Nodes {
+			total += n.StargazerCount
+		}
+		return data.User.Repositories.PageInfo, nil
+	})
+	return total, err
+}
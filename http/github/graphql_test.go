@@ -0,0 +1,177 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsMutation(t *testing.T) {
+	for _, c := range []struct {
+		query string
+		want  bool
+	}{
+		{`query { viewer { login } }`, false},
+		{`{ viewer { login } }`, false},
+		{`mutation { addStar(input: {starrableId: "x"}) { clientMutationId } }`, true},
+		{"  \n\tmutation AddStar($id: ID!) { addStar(input: {starrableId: $id}) { clientMutationId } }", true},
+		{"# star it\nmutation { addStar(input: {starrableId: \"x\"}) { clientMutationId } }", true},
+		{"fragment F on Repository { id }\nmutation { addStar(input: {starrableId: \"x\"}) { starrable { ...F } } }", true},
+		{"query Q { a }\nmutation M { b }", true},
+		{"# mutation\nquery { viewer { login } }", false},
+		{`query { search(query: "mutation } {") { issueCount } }`, false},
+		{`query($q: String = "mutation") { search(query: $q) { issueCount } }`, false},
+		{`query { a(s: """ "mutation" } """) }`, false},
+		{`query mutationCount { viewer { login } }`, false},
+		{`query { mutation }`, false},
+	} {
+		if got := isMutation(c.query); got != c.want {
+			t.Errorf("isMutation(%q) = %v, want %v", c.query, got, c.want)
+		}
+	}
+}
+
+// TestGraphQLMutationNotRetried checks that a mutation isn't retried after a
+// 502 even when a comment comes first, while a query is.
+func TestGraphQLMutationNotRetried(t *testing.T) {
+	for _, c := range []struct {
+		query    string
+		attempts int
+	}{
+		{"query { viewer { login } }", 2},
+		{"# star\nmutation { addStar(input: {starrableId: \"x\"}) { clientMutationId } }", 1},
+	} {
+		client, bodies := scripted(t, reply{status: http.StatusBadGateway}, reply{status: http.StatusOK, body: `{"data": {}}`})
+		client.GraphQL(context.Background(), c.query, nil, nil)
+		if n := len(bodies()); n != c.attempts {
+			t.Errorf("%q: %d attempts, want %d", c.query, n, c.attempts)
+		}
+	}
+}
+
+// graphqlServer answers every GraphQL request with respond of its variables.
+func graphqlServer(t *testing.T, respond func(vars map[string]any) string) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if r.URL.Path != "/graphql" || json.NewDecoder(r.Body).Decode(&req) != nil || req.Query == "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, respond(req.Variables))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.BaseURL = srv.URL
+	c.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return c
+}
+
+func TestGraphQLDataAndErrors(t *testing.T) {
+	c := graphqlServer(t, func(map[string]any) string {
+		return `{
+		  "data": {"alice": {"login": "alice"}, "nobody": null},
+		  "errors": [{
+		    "type": "NOT_FOUND",
+		    "path": ["nobody"],
+		    "locations": [{"line": 1, "column": 40}],
+		    "message": "Could not resolve to a User with the login of 'nobody'."
+		  }]
+		}`
+	})
+	var data struct {
+		Alice  *struct{ Login string } `json:"alice"`
+		Nobody *struct{ Login string } `json:"nobody"`
+	}
+	err := c.GraphQL(context.Background(), `{ alice: user(login: "alice") { login } nobody: user(login: "nobody") { login } }`, nil, &data)
+
+	var gqlErrs GraphQLErrors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) != 1 || gqlErrs[0].Locations[0].Column != 40 {
+		t.Fatalf("got error %#v, want one GraphQLError", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("%v doesn't match ErrNotFound", err)
+	}
+	if data.Alice == nil || data.Alice.Login != "alice" || data.Nobody != nil {
+		t.Errorf("data %+v, want alice decoded next to the error", data)
+	}
+	if want := "github: graphql: NOT_FOUND: Could not resolve to a User with the login of 'nobody'."; err.Error() != want {
+		t.Errorf("error %q, want %q", err, want)
+	}
+
+	forbidden := GraphQLErrors{{Type: "FORBIDDEN", Message: "Resource not accessible by integration"}}
+	if errors.Is(forbidden, ErrNotFound) {
+		t.Error("a FORBIDDEN error matches ErrNotFound")
+	}
+}
+
+func TestProfile(t *testing.T) {
+	c := graphqlServer(t, func(vars map[string]any) string {
+		if vars["login"] != "alice" {
+			return `{"data": {"user": null}, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a User"}]}`
+		}
+		return `{"data": {"user": {
+		  "login": "alice", "name": "Alice",
+		  "followers": {"totalCount": 12},
+		  "repositories": {"totalCount": 3, "nodes": [
+		    {"name": "app", "nameWithOwner": "alice/app", "stargazerCount": 40, "forkCount": 2, "primaryLanguage": {"name": "Go"}},
+		    {"name": "notes", "nameWithOwner": "alice/notes", "stargazerCount": 1, "isArchived": true, "primaryLanguage": null}
+		  ]},
+		  "contributionsCollection": {"contributionCalendar": {"totalContributions": 321}}
+		}}}`
+	})
+
+	p, err := c.Profile(context.Background(), "alice", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Alice" || p.Followers != 12 || p.Repos != 3 || p.Contributions != 321 || len(p.TopRepos) != 2 {
+		t.Errorf("got %+v", p)
+	} else if r := p.TopRepos[0]; r.FullName != "alice/app" || r.Stars != 40 || r.Language != "Go" || !p.TopRepos[1].Archived {
+		t.Errorf("top repos %+v", p.TopRepos)
+	}
+
+	if _, err := c.Profile(context.Background(), "nobody", 2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestGraphQLPaginate(t *testing.T) {
+	var afters []any
+	pages := map[any]string{
+		nil:  `{"pageInfo": {"hasNextPage": true, "endCursor": "c1"}, "nodes": [{"stargazerCount": 1}, {"stargazerCount": 2}]}`,
+		"c1": `{"pageInfo": {"hasNextPage": true, "endCursor": "c2"}, "nodes": [{"stargazerCount": 10}]}`,
+		"c2": `{"pageInfo": {"hasNextPage": false, "endCursor": "c3"}, "nodes": [{"stargazerCount": 100}]}`,
+	}
+	c := graphqlServer(t, func(vars map[string]any) string {
+		afters = append(afters, vars["after"])
+		return `{"data": {"user": {"repositories": ` + pages[vars["after"]] + `}}}`
+	})
+
+	total, err := c.TotalStars(context.Background(), "alice")
+	if err != nil || total != 113 {
+		t.Errorf("got %d, %v, want 113", total, err)
+	}
+	if len(afters) != 3 || afters[0] != nil || afters[1] != "c1" || afters[2] != "c2" {
+		t.Errorf("cursors %v, want [<nil> c1 c2]", afters)
+	}
+
+	// an error of page stops the pagination
+	afters = nil
+	stop := errors.New("stop")
+	err = c.GraphQLPaginate(context.Background(), starsQuery, map[string]any{"login": "alice"}, func(json.RawMessage) (PageInfo, error) {
+		return PageInfo{}, stop
+	})
+	if err != stop || len(afters) != 1 {
+		t.Errorf("got %v after %d pages, want stop after 1", err, len(afters))
+	}
+}
@@ -0,0 +1,189 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the latency histogram.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics collects what the Client sees on the wire: request latencies,
+// status codes and the rate limit GitHub reports. It serves them in the
+// Prometheus text format, mount it on /metrics:
+//
+//	m := github.NewMetrics()
+//	c.Metrics = m
+//	http.Handle("/metrics", m)
+type Metrics struct {
+	mu        sync.Mutex
+	latency   map[string]*histogram // by method
+	requests  map[[2]string]int64   // by method, status code ("error" for transport errors)
+	rateLimit map[string]rateLimit  // by X-RateLimit-Resource
+}
+
+type histogram struct {
+	counts []int64 // one per latencyBuckets, not cumulative
+	sum    float64
+	count  int64
+}
+
+type rateLimit struct {
+	limit, remaining int64
+	reset            int64 // unix seconds
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		latency:   make(map[string]*histogram),
+		requests:  make(map[[2]string]int64),
+		rateLimit: make(map[string]rateLimit),
+	}
+}
+
+// observe records one attempt. resp is nil when err is set.
+func (m *Metrics) observe(method string, resp *http.Response, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.latency[method]
+	if !ok {
+		h = &histogram{counts: make([]int64, len(latencyBuckets))}
+		m.latency[method] = h
+	}
+	s := d.Seconds()
+	for i, le := range latencyBuckets {
+		if s <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += s
+	h.count++
+
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	m.requests[[2]string{method, code}]++
+
+	if resp == nil || resp.Header.Get("X-RateLimit-Limit") == "" {
+		return
+	}
+	res := resp.Header.Get("X-RateLimit-Resource")
+	if res == "" {
+		res = "core"
+	}
+	var rl rateLimit
+	rl.limit, _ = strconv.ParseInt(resp.Header.Get("X-RateLimit-Limit"), 10, 64)
+	rl.remaining, _ = strconv.ParseInt(resp.Header.Get("X-RateLimit-Remaining"), 10, 64)
+	rl.reset, _ = strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	m.rateLimit[res] = rl
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP github_request_duration_seconds Latency of GitHub API requests, one per attempt.\n")
+	b.WriteString("# TYPE github_request_duration_seconds histogram\n")
+	for _, method := range sortedKeys(m.latency) {
+		h := m.latency[method]
+		var cum int64
+		for i, le := range latencyBuckets {
+			cum += h.counts[i]
+			fmt.Fprintf(&b, "github_request_duration_seconds_bucket{method=%q,le=%q} %d\n", method, strconv.FormatFloat(le, 'g', -1, 64), cum)
+		}
+		fmt.Fprintf(&b, "github_request_duration_seconds_bucket{method=%q,le=\"+Inf\"} %d\n", method, h.count)
+		fmt.Fprintf(&b, "github_request_duration_seconds_sum{method=%q} %g\n", method, h.sum)
+		fmt.Fprintf(&b, "github_request_duration_seconds_count{method=%q} %d\n", method, h.count)
+	}
+
+	b.WriteString("# HELP github_requests_total GitHub API requests by method and status code.\n")
+	b.WriteString("# TYPE github_requests_total counter\n")
+	keys := make([][2]string, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, k := range keys {
+		fmt.Fprintf(&b, "github_requests_total{method=%q,code=%q} %d\n", k[0], k[1], m.requests[k])
+	}
+
+	gauges := []struct {
+		name, help string
+		value      func(rateLimit) int64
+	}{
+		{"github_rate_limit_remaining", "Requests left in the current rate limit window.", func(r rateLimit) int64 { return r.remaining }},
+		{"github_rate_limit_limit", "Requests allowed per rate limit window.", func(r rateLimit) int64 { return r.limit }},
+		{"github_rate_limit_reset_timestamp_seconds", "When the current rate limit window resets.", func(r rateLimit) int64 { return r.reset }},
+	}
+	for _, g := range gauges {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+		for _, res := range sortedKeys(m.rateLimit) {
+			fmt.Fprintf(&b, "%s{resource=%q} %d\n", g.name, res, g.value(m.rateLimit[res]))
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RequestIDHeader carries the request ID to GitHub, next to the
+// X-GitHub-Request-Id GitHub assigns itself.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// WithRequestID makes the requests sent with ctx use id as their request ID,
+// otherwise the Client generates one per request (shared by its retries).
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID of ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
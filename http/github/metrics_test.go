@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"main/http/fakegithub"
+)
+
+// TestMetricsFake checks the exposition after requests to the fake, whose
+// 404s have no rate limit headers.
+func TestMetricsFake(t *testing.T) {
+	c, _ := newFake(t, &fakegithub.Seed{Users: []map[string]any{{"login": "alice"}}, RateLimit: 60})
+	c.Metrics = NewMetrics()
+	for _, login := range []string{"alice", "alice", "nobody"} {
+		c.User(context.Background(), login)
+	}
+
+	w := httptest.NewRecorder()
+	c.Metrics.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type %q", ct)
+	}
+	text := w.Body.String()
+	for _, want := range []string{
+		`github_requests_total{method="GET",code="200"} 2`,
+		`github_requests_total{method="GET",code="404"} 1`,
+		`github_request_duration_seconds_bucket{method="GET",le="+Inf"} 3`,
+		`github_request_duration_seconds_count{method="GET"} 3`,
+		`github_rate_limit_remaining{resource="core"} 58`,
+		`github_rate_limit_limit{resource="core"} 60`,
+		`# TYPE github_rate_limit_reset_timestamp_seconds gauge`,
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("no %s in\n%s", want, text)
+		}
+	}
+}
+
+// TestMetricsObserve checks the histogram buckets, which are cumulative with
+// inclusive upper bounds, and the gauges of each rate limit resource.
+func TestMetricsObserve(t *testing.T) {
+	m := NewMetrics()
+	ok := func(resource, remaining string) *http.Response {
+		h := http.Header{}
+		h.Set("X-RateLimit-Limit", "5000")
+		h.Set("X-RateLimit-Remaining", remaining)
+		h.Set("X-RateLimit-Reset", "1700000000")
+		if resource != "" {
+			h.Set("X-RateLimit-Resource", resource)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: h}
+	}
+	m.observe("GET", ok("", "4999"), 10*time.Millisecond)
+	m.observe("GET", ok("core", "4998"), 50*time.Millisecond)
+	m.observe("GET", ok("search", "29"), 300*time.Millisecond)
+	m.observe("GET", nil, 45*time.Second)
+	m.observe("POST", &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}}, 2*time.Second)
+
+	var b strings.Builder
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP github_request_duration_seconds Latency of GitHub API requests, one per attempt.
+# TYPE github_request_duration_seconds histogram
+github_request_duration_seconds_bucket{method="GET",le="0.05"} 2
+github_request_duration_seconds_bucket{method="GET",le="0.1"} 2
+github_request_duration_seconds_bucket{method="GET",le="0.25"} 2
+github_request_duration_seconds_bucket{method="GET",le="0.5"} 3
+github_request_duration_seconds_bucket{method="GET",le="1"} 3
+github_request_duration_seconds_bucket{method="GET",le="2.5"} 3
+github_request_duration_seconds_bucket{method="GET",le="5"} 3
+github_request_duration_seconds_bucket{method="GET",le="10"} 3
+github_request_duration_seconds_bucket{method="GET",le="30"} 3
+github_request_duration_seconds_bucket{method="GET",le="+Inf"} 4
+github_request_duration_seconds_sum{method="GET"} 45.36
+github_request_duration_seconds_count{method="GET"} 4
+github_request_duration_seconds_bucket{method="POST",le="0.05"} 0
+github_request_duration_seconds_bucket{method="POST",le="0.1"} 0
+github_request_duration_seconds_bucket{method="POST",le="0.25"} 0
+github_request_duration_seconds_bucket{method="POST",le="0.5"} 0
+github_request_duration_seconds_bucket{method="POST",le="1"} 0
+github_request_duration_seconds_bucket{method="POST",le="2.5"} 1
+github_request_duration_seconds_bucket{method="POST",le="5"} 1
+github_request_duration_seconds_bucket{method="POST",le="10"} 1
+github_request_duration_seconds_bucket{method="POST",le="30"} 1
+github_request_duration_seconds_bucket{method="POST",le="+Inf"} 1
+github_request_duration_seconds_sum{method="POST"} 2
+github_request_duration_seconds_count{method="POST"} 1
+# HELP github_requests_total GitHub API requests by method and status code.
+# TYPE github_requests_total counter
+github_requests_total{method="GET",code="200"} 3
+github_requests_total{method="GET",code="error"} 1
+github_requests_total{method="POST",code="502"} 1
+# HELP github_rate_limit_remaining Requests left in the current rate limit window.
+# TYPE github_rate_limit_remaining gauge
+github_rate_limit_remaining{resource="core"} 4998
+github_rate_limit_remaining{resource="search"} 29
+# HELP github_rate_limit_limit Requests allowed per rate limit window.
+# TYPE github_rate_limit_limit gauge
+github_rate_limit_limit{resource="core"} 5000
+github_rate_limit_limit{resource="search"} 5000
+# HELP github_rate_limit_reset_timestamp_seconds When the current rate limit window resets.
+# TYPE github_rate_limit_reset_timestamp_seconds gauge
+github_rate_limit_reset_timestamp_seconds{resource="core"} 1700000000
+github_rate_limit_reset_timestamp_seconds{resource="search"} 1700000000
+`
+	if got := b.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestMetricsNil checks that observing with a Client without Metrics is a no-op.
+func TestMetricsNil(t *testing.T) {
+	var m *Metrics
+	m.observe("GET", nil, time.Second)
+}
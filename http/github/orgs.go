@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"net/url"
+)
+
+// Team is a team of an organization.
+type Team struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Privacy     string `json:"privacy"`
+}
+
+// TeamRepo is a repository a team has access to.
+type TeamRepo struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	// RoleName is the team's role on the repository:
+	// "read", "triage", "write", "maintain", "admin" or a custom role.
+	RoleName    string `json:"role_name"`
+	Permissions struct {
+		Admin    bool `json:"admin"`
+		Maintain bool `json:"maintain"`
+		Push     bool `json:"push"`
+		Triage   bool `json:"triage"`
+		Pull     bool `json:"pull"`
+	} `json:"permissions"`
+}
+
+// Role returns RoleName, or the highest permission when GitHub didn't send a role name.
+func (r TeamRepo) Role() string {
+	p := r.Permissions
+	switch {
+	case r.RoleName != "":
+		return r.RoleName
+	case p.Admin:
+		return "admin"
+	case p.Maintain:
+		return "maintain"
+	case p.Push:
+		return "write"
+	case p.Triage:
+		return "triage"
+	case p.Pull:
+		return "read"
+	}
+	return ""
+}
+
+// MemberFilter narrows ListOrgMembers.
+type MemberFilter struct {
+	// Role is "all" (default), "admin" or "member".
+	Role string
+	// TwoFactorDisabled keeps only the members without two-factor authentication.
+	// Only organization owners can use it.
+	TwoFactorDisabled bool
+}
+
+// ListOrgMembers iterates over the members of org, see ListRepos for how to call the iterator.
+func (c *Client) ListOrgMembers(ctx context.Context, org string, f *MemberFilter) func(yield func(Account, error) bool) {
+	v := url.Values{"per_page": {"100"}}
+	if f != nil && f.Role != "" {
+		v.Set("role", f.Role)
+	}
+	if f != nil && f.TwoFactorDisabled {
+		v.Set("filter", "2fa_disabled")
+	}
+	return paginate[Account](ctx, c, "orgs/"+url.PathEscape(org)+"/members", v)
+}
+
+// ListOutsideCollaborators iterates over the users with access to repositories of org
+// without being members of it.
+func (c *Client) ListOutsideCollaborators(ctx context.Context, org string) func(yield func(Account, error) bool) {
+	return paginate[Account](ctx, c, "orgs/"+url.PathEscape(org)+"/outside_collaborators", url.Values{"per_page": {"100"}})
+}
+
+// ListTeams iterates over the teams of org.
+func (c *Client) ListTeams(ctx context.Context, org string) func(yield func(Team, error) bool) {
+	return paginate[Team](ctx, c, "orgs/"+url.PathEscape(org)+"/teams", url.Values{"per_page": {"100"}})
+}
+
+// ListTeamMembers iterates over the members of the team slug, including the members of its child teams.
+func (c *Client) ListTeamMembers(ctx context.Context, org, slug string) func(yield func(Account, error) bool) {
+	return paginate[Account](ctx, c, "orgs/"+url.PathEscape(org)+"/teams/"+url.PathEscape(slug)+"/members", url.Values{"per_page": {"100"}})
+}
+
+// ListTeamRepos iterates over the repositories the team slug has access to, with its role on each.
+func (c *Client) ListTeamRepos(ctx context.Context, org, slug string) func(yield func(TeamRepo, error) bool) {
+	return paginate[TeamRepo](ctx, c, "orgs/"+url.PathEscape(org)+"/teams/"+url.PathEscape(slug)+"/repos", url.Values{"per_page": {"100"}})
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket shared by every request of a Client.
+// It spaces requests out to a steady rate with some burst, and holds
+// everybody back once GitHub reports that the hourly quota is used up.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	// blockedUntil is X-RateLimit-Reset after a response with X-RateLimit-Remaining: 0
+	blockedUntil time.Time
+}
+
+// NewLimiter allows perSecond requests on average and bursts of up to burst.
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// take the token now, going negative, so concurrent callers queue up behind each other
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 && l.rate > 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if d := l.blockedUntil.Sub(now); d > wait {
+		wait = d
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	return sleep(ctx, wait)
+}
+
+// observe reads GitHub's rate limit headers off resp.
+func (l *Limiter) observe(resp *http.Response) {
+	if l == nil || resp == nil || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	s, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if reset := time.Unix(s, 0); reset.After(l.blockedUntil) {
+		l.blockedUntil = reset
+	}
+}
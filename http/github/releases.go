@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Readme is a repository's README as returned by GET /repos/{owner}/{repo}/readme.
+type Readme struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	SHA         string `json:"sha"`
+	Size        int    `json:"size"`
+	HTMLURL     string `json:"html_url"`
+	DownloadURL string `json:"download_url"`
+	// Encoding is "base64", Content is the file encoded with it.
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+// Text decodes Content.
+func (r *Readme) Text() ([]byte, error) {
+	if r.Encoding != "base64" {
+		return nil, fmt.Errorf("github: readme %s: unsupported encoding %q", r.Path, r.Encoding)
+	}
+	// GitHub wraps the base64 at 60 columns
+	return base64.StdEncoding.DecodeString(strings.ReplaceAll(r.Content, "\n", ""))
+}
+
+// Readme fetches the README of owner/repo at ref, the default branch when ref is "".
+// Use Readme.Text for its content.
+func (c *Client) Readme(ctx context.Context, owner, repo, ref string) (*Readme, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, readmePath(owner, repo, ref), nil)
+	if err != nil {
+		return nil, err
+	}
+	var r Readme
+	if err := c.Do(req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ReadmeRaw fetches the README of owner/repo at ref with the raw media type,
+// which skips the base64 round trip and works for files up to 100MB.
+func (c *Client) ReadmeRaw(ctx context.Context, owner, repo, ref string) ([]byte, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, readmePath(owner, repo, ref), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw+json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(c.limitBody(resp))
+	return b, bodyErr(req, err)
+}
+
+func readmePath(owner, repo, ref string) string {
+	p := "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/readme"
+	if ref != "" {
+		p += "?ref=" + url.QueryEscape(ref)
+	}
+	return p
+}
+
+// Release is a GitHub release.
+type Release struct {
+	ID      int64  `json:"id"`
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	// Body is the release notes, in Markdown.
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Label         string `json:"label"`
+	ContentType   string `json:"content_type"`
+	Size          int64  `json:"size"`
+	DownloadCount int    `json:"download_count"`
+	// BrowserDownloadURL downloads the asset without the API, and without counting against the rate limit.
+	BrowserDownloadURL string `json:"browser_download_url"`
+	// Digest is the asset's "sha256:<hex>" sum, GitHub only computes it for assets uploaded since mid 2025.
+	Digest string `json:"digest"`
+}
+
+// ListReleases iterates over the releases of owner/repo, newest first.
+// Drafts are only listed for users with push access.
+func (c *Client) ListReleases(ctx context.Context, owner, repo string) func(yield func(Release, error) bool) {
+	return paginate[Release](ctx, c, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases", url.Values{"per_page": {"100"}})
+}
+
+// LatestRelease fetches the most recent published release of owner/repo,
+// drafts and prereleases excluded.
+func (c *Client) LatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	return c.release(ctx, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases/latest")
+}
+
+// ReleaseByTag fetches the release of owner/repo tagged tag.
+func (c *Client) ReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error) {
+	return c.release(ctx, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases/tags/"+url.PathEscape(tag))
+}
+
+func (c *Client) release(ctx context.Context, path string) (*Release, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var r Release
+	if err := c.Do(req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// DownloadAsset copies the content of the asset id of owner/repo to w.
+// GitHub redirects to its storage, the redirect is followed without the token.
+// MaxBodyBytes doesn't apply, assets are often larger than API responses.
+func (c *Client) DownloadAsset(ctx context.Context, owner, repo string, id int64, w io.Writer) (int64, error) {
+	path := "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/releases/assets/" + strconv.FormatInt(id, 10)
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return io.Copy(w, resp.Body)
+}
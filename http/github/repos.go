@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repo is the subset of a GitHub repository object we care about.
+type Repo struct {
+	Name     string    `json:"name"`
+	FullName string    `json:"full_name"`
+	Language string    `json:"language"`
+	Stars    int       `json:"stargazers_count"`
+	Forks    int       `json:"forks_count"`
+	Archived bool      `json:"archived"`
+	PushedAt time.Time `json:"pushed_at"`
+}
+
+// ListReposOptions are the query parameters of GET /users/{user}/repos.
+// Zero fields are left to GitHub's defaults.
+type ListReposOptions struct {
+	// Type is one of "all", "owner" or "member".
+	Type string
+	// Sort is one of "created", "updated", "pushed" or "full_name".
+	Sort string
+	// Direction is "asc" or "desc".
+	Direction string
+	// PerPage is the page size, GitHub caps it at 100.
+	PerPage int
+}
+
+func (o *ListReposOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	if o.Type != "" {
+		v.Set("type", o.Type)
+	}
+	if o.Sort != "" {
+		v.Set("sort", o.Sort)
+	}
+	if o.Direction != "" {
+		v.Set("direction", o.Direction)
+	}
+	if o.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	return v
+}
+
+// ListRepos returns an iterator over the public repositories of user.
+// Pages are fetched lazily by following the Link: rel="next" header.
+//
+// The iterator has the shape of Go 1.23's iter.Seq2, so in Go 1.21 it is
+// called with a callback that returns false to stop early:
+//
+//	c.ListRepos(ctx, "golang", nil)(func(r github.Repo, err error) bool {
+//		if err != nil {
+//			log.Print(err)
+//			return false
+//		}
+//		fmt.Println(r.Name)
+//		return true
+//	})
+//
+// An error is yielded at most once, as the last value.
+func (c *Client) ListRepos(ctx context.Context, user string, opts *ListReposOptions) func(yield func(Repo, error) bool) {
+	return paginate[Repo](ctx, c, "users/"+url.PathEscape(user)+"/repos", opts.values())
+}
+
+// CollectRepos fetches every page of ListRepos into a slice.
+func (c *Client) CollectRepos(ctx context.Context, user string, opts *ListReposOptions) ([]Repo, error) {
+	return Collect(c.ListRepos(ctx, user, opts))
+}
+
+// paginate walks a list endpoint page by page, yielding every element as it is decoded.
+func paginate[T any](ctx context.Context, c *Client, path string, query url.Values) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		var zero T
+		next := path
+		if len(query) > 0 {
+			next += "?" + query.Encode()
+		}
+
+		for next != "" {
+			req, err := c.NewRequest(ctx, http.MethodGet, next, nil)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			resp, err := c.do(req)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// decode the page element by element, pages of large objects are never held in memory whole
+			stopped := false
+			err = DecodeArray(c.limitBody(resp), func(v T) error {
+				if !yield(v, nil) {
+					stopped = true
+					return errStop
+				}
+				return nil
+			})
+			resp.Body.Close()
+			if stopped {
+				return
+			}
+			if err != nil {
+				yield(zero, bodyErr(req, err))
+				return
+			}
+			next = nextLink(resp.Header)
+		}
+	}
+}
+
+// Collect drains one of the iterators of this package into a slice, stopping at the first error.
+//
+//	members, err := github.Collect(c.ListOrgMembers(ctx, "golang", nil))
+func Collect[T any](seq func(yield func(T, error) bool)) ([]T, error) {
+	var (
+		out []T
+		err error
+	)
+	seq(func(v T, e error) bool {
+		if e != nil {
+			err = e
+			return false
+		}
+		out = append(out, v)
+		return true
+	})
+	return out, err
+}
+
+// nextLink returns the rel="next" URL of an RFC 5988 Link header, or "".
+//
+//	Link: <https://api.github.com/user/1/repos?page=2>; rel="next", <...?page=5>; rel="last"
+func nextLink(h http.Header) string {
+	for _, line := range h.Values("Link") {
+		for _, link := range strings.Split(line, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(k, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(v, `"`)) {
+					if rel == "next" {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
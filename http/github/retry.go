@@ -0,0 +1,150 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures how Client retries transient failures:
+// 5xx responses, 429, secondary rate limits and network timeouts.
+// Only idempotent requests (GET, HEAD, OPTIONS, TRACE, PUT, DELETE) and GraphQL
+// queries are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first one.
+	// 0 or 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is the backoff cap of the first retry, it doubles every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than this is not waited for.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// backoff returns how long to wait after a failed attempt (1-based).
+// It uses "full jitter": a random duration in [0, min(MaxDelay, BaseDelay*2^(attempt-1))].
+// A server provided hint (Retry-After, rate limit reset) is a lower bound.
+func (p RetryPolicy) backoff(attempt int, hint time.Duration) time.Duration {
+	// double BaseDelay up to the cap instead of shifting it, which overflows
+	// after a few dozen attempts and wraps to a small or negative delay
+	limit := p.MaxDelay
+	if limit <= 0 || limit > math.MaxInt64/2 {
+		limit = math.MaxInt64 / 2
+	}
+	ceil := min(p.BaseDelay, limit)
+	for i := 1; i < attempt && 0 < ceil && ceil < limit; i++ {
+		ceil = min(2*ceil, limit)
+	}
+
+	var d time.Duration
+	if ceil > 0 {
+		d = time.Duration(rand.Int63n(int64(ceil) + 1))
+	}
+	if hint > d {
+		d = hint
+	}
+	return d
+}
+
+type idempotentKey struct{}
+
+// withIdempotent marks the requests made with ctx as safe to retry whatever
+// their method, e.g. GraphQL queries which are sent with POST.
+func withIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	ok, _ := req.Context().Value(idempotentKey{}).(bool)
+	return ok
+}
+
+// retryReason reports why the outcome of an attempt is worth retrying,
+// or "" if it isn't. hint is the minimum wait the server asked for.
+func retryReason(resp *http.Response, err error) (reason string, hint time.Duration) {
+	if err != nil {
+		var ne net.Error
+		if errors.As(err, &ne) && ne.Timeout() && !errors.Is(err, context.DeadlineExceeded) {
+			return "timeout: " + err.Error(), 0
+		}
+		return "", 0
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "rate limited: " + resp.Status, rateLimitWait(resp)
+	case resp.StatusCode == http.StatusForbidden && isSecondaryRateLimit(resp):
+		return "secondary rate limit: " + resp.Status, rateLimitWait(resp)
+	case resp.StatusCode >= 500:
+		return "server error: " + resp.Status, 0
+	}
+	return "", 0
+}
+
+// isSecondaryRateLimit peeks at a 403 body for GitHub's secondary rate limit message.
+// The body is put back so it can still be decoded.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Header.Get("Retry-After") != "" {
+		return true
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(bytes.ToLower(b), []byte("secondary rate limit"))
+}
+
+// rateLimitWait returns the wait GitHub asks for with Retry-After,
+// or until X-RateLimit-Reset when the primary limit is exhausted.
+func rateLimitWait(resp *http.Response) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if s, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return time.Duration(s) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return time.Until(t)
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if s, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Until(time.Unix(s, 0))
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("github: retry aborted: %w", ctx.Err())
+	}
+}
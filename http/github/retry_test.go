@@ -0,0 +1,180 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// reply is a canned response of a scripted server.
+type reply struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+// scripted serves replies in order, repeating the last one, and records the
+// bodies of the requests it got.
+func scripted(t *testing.T, replies ...reply) (*Client, func() []string) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		rp := replies[min(len(bodies), len(replies)-1)]
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+
+		for k, v := range rp.header {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(rp.status)
+		io.WriteString(w, rp.body)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.BaseURL = srv.URL
+	c.Retry = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+	c.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return bodies
+	}
+}
+
+var ok = reply{status: http.StatusOK, body: `{"login": "alice"}`}
+
+func TestRetry(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		method   string
+		ctx      context.Context
+		replies  []reply
+		attempts int
+		wantErr  error
+	}{
+		{"server errors then success", http.MethodGet, nil, []reply{{status: 502}, {status: 503}, ok}, 3, nil},
+		{"server errors until giving up", http.MethodGet, nil, []reply{{status: 500}}, 4, ErrServer},
+		{"PUT is retried", http.MethodPut, nil, []reply{{status: 500}, ok}, 2, nil},
+		{"POST is not retried", http.MethodPost, nil, []reply{{status: 502}, ok}, 1, ErrServer},
+		{"GraphQL POST is retried", http.MethodPost, withIdempotent(context.Background()), []reply{{status: 502}, ok}, 2, nil},
+		{"429", http.MethodGet, nil, []reply{{status: 429, header: map[string]string{"Retry-After": "0"}}, ok}, 2, nil},
+		{"secondary rate limit", http.MethodGet, nil, []reply{
+			{status: 403, body: `{"message": "You have exceeded a secondary rate limit."}`}, ok,
+		}, 2, nil},
+		{"403 is not retried", http.MethodGet, nil, []reply{{status: 403, body: `{"message": "Resource not accessible"}`}, ok}, 1, ErrForbidden},
+		{"404 is not retried", http.MethodGet, nil, []reply{{status: 404}, ok}, 1, ErrNotFound},
+		{"Retry-After over MaxDelay is not waited for", http.MethodGet, nil, []reply{
+			{status: 429, header: map[string]string{"Retry-After": "60"}}, ok,
+		}, 1, ErrRateLimited},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			client, bodies := scripted(t, c.replies...)
+			ctx := c.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req, err := client.NewRequest(ctx, c.method, "users/alice", strings.NewReader("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var u User
+			err = client.Do(req, &u)
+			if !errors.Is(err, c.wantErr) || (err == nil && u.Login != "alice") {
+				t.Errorf("got %+v, %v, want error %v", u, err, c.wantErr)
+			}
+			got := bodies()
+			if len(got) != c.attempts {
+				t.Errorf("%d attempts, want %d", len(got), c.attempts)
+			}
+			for i, b := range got {
+				if b != "payload" {
+					t.Errorf("attempt %d sent body %q", i+1, b)
+				}
+			}
+		})
+	}
+}
+
+// TestRetryAfter checks that the wait asked for with Retry-After is a lower
+// bound of the backoff.
+func TestRetryAfter(t *testing.T) {
+	client, bodies := scripted(t, reply{status: 429, header: map[string]string{"Retry-After": "1"}}, ok)
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "users/alice", nil)
+	start := time.Now()
+	if err := client.Do(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < time.Second || len(bodies()) != 2 {
+		t.Errorf("%d attempts in %v, want 2 a second apart", len(bodies()), d)
+	}
+}
+
+// TestRetryCanceled checks that a canceled context stops the wait between attempts.
+func TestRetryCanceled(t *testing.T) {
+	client, bodies := scripted(t, reply{status: 429, header: map[string]string{"Retry-After": "1"}})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := client.NewRequest(ctx, http.MethodGet, "users/alice", nil)
+	start := time.Now()
+	err := client.Do(req, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || len(bodies()) != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("got %v after %d attempts in %v, want the context's error after 1", err, len(bodies()), time.Since(start))
+	}
+}
+
+// TestDoLeavesRequest checks that the request ID is set on a copy, so a
+// request can be sent again without carrying the previous ID.
+func TestDoLeavesRequest(t *testing.T) {
+	client, _ := scripted(t, ok)
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "users/alice", nil)
+	if err := client.Do(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if id := req.Header.Get(RequestIDHeader); id != "" {
+		t.Errorf("caller's request has %s: %s", RequestIDHeader, id)
+	}
+}
+
+// TestBackoffBounds checks that the backoff stays within [hint, MaxDelay]
+// and reaches MaxDelay however many attempts were made, where shifting
+// BaseDelay would overflow.
+func TestBackoffBounds(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 3 * time.Millisecond, MaxDelay: 2 * time.Second}
+	for _, attempt := range []int{1, 2, 10, 40, 63, 64, 65, 100, 1 << 20} {
+		longest := time.Duration(0)
+		for i := 0; i < 200; i++ {
+			d := p.backoff(attempt, 0)
+			if d < 0 || d > p.MaxDelay {
+				t.Fatalf("attempt %d: backoff %v out of [0, %v]", attempt, d, p.MaxDelay)
+			}
+			longest = max(longest, d)
+		}
+		if attempt >= 10 && longest < p.MaxDelay/2 {
+			t.Errorf("attempt %d: longest of 200 backoffs %v, want close to %v", attempt, longest, p.MaxDelay)
+		}
+	}
+
+	if d := p.backoff(1, 5*time.Second); d != 5*time.Second {
+		t.Errorf("backoff with a hint of 5s = %v", d)
+	}
+	uncapped := RetryPolicy{BaseDelay: time.Second}
+	if d := uncapped.backoff(1000, 0); d < 0 {
+		t.Errorf("uncapped backoff of attempt 1000 = %v", d)
+	}
+	if d := (RetryPolicy{MaxDelay: time.Second}).backoff(5, 0); d != 0 {
+		t.Errorf("backoff without BaseDelay = %v, want 0", d)
+	}
+}
@@ -0,0 +1,265 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchWindow is the number of results GitHub returns for a search query,
+// whatever its total_count.
+const searchWindow = 1000
+
+// searchEpoch is the earliest date worth searching, GitHub launched in 2008.
+var searchEpoch = time.Date(2007, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// Query builds a search query: free text terms plus qualifiers.
+//
+//	q := github.NewQuery("http client").Language("go").Stars(github.Gt(100)).Org("golang")
+//	q.String() // "\"http client\" language:go stars:>100 org:golang"
+type Query struct {
+	terms   []string
+	quals   []string
+	created *dateRange
+}
+
+type dateRange struct{ from, to time.Time }
+
+// NewQuery starts a query matching all of terms.
+func NewQuery(terms ...string) *Query {
+	q := &Query{}
+	for _, t := range terms {
+		q.terms = append(q.terms, quote(t))
+	}
+	return q
+}
+
+// Qualifier adds key:value, quoting value when needed.
+// Range values from Gt, Lt, Between etc. are passed through as is.
+func (q *Query) Qualifier(key, value string) *Query {
+	q.quals = append(q.quals, key+":"+quote(value))
+	return q
+}
+
+func (q *Query) Language(lang string) *Query { return q.Qualifier("language", lang) }
+func (q *Query) Org(org string) *Query       { return q.Qualifier("org", org) }
+func (q *Query) User(user string) *Query     { return q.Qualifier("user", user) }
+func (q *Query) Repo(fullName string) *Query { return q.Qualifier("repo", fullName) }
+func (q *Query) Is(state string) *Query      { return q.Qualifier("is", state) }
+
+// Stars filters repositories on their star count, e.g. Stars(Gte(50)).
+func (q *Query) Stars(r string) *Query { return q.Qualifier("stars", r) }
+
+// Followers filters users on their follower count.
+func (q *Query) Followers(r string) *Query { return q.Qualifier("followers", r) }
+
+// Created keeps the results created between from and to, both days included.
+// A zero time leaves that end open. The range is what the Search methods split when a
+// query has more results than GitHub is willing to return.
+func (q *Query) Created(from, to time.Time) *Query {
+	q.created = &dateRange{from, to}
+	return q
+}
+
+func (q *Query) String() string {
+	parts := append(append([]string{}, q.terms...), q.quals...)
+	if r := q.created; r != nil {
+		parts = append(parts, "created:"+dateBound(r.from)+".."+dateBound(r.to))
+	}
+	return strings.Join(parts, " ")
+}
+
+// withCreated returns a copy of q restricted to another creation range.
+func (q *Query) withCreated(from, to time.Time) *Query {
+	c := *q
+	c.created = &dateRange{from, to}
+	return &c
+}
+
+func dateBound(t time.Time) string {
+	if t.IsZero() {
+		return "*"
+	}
+	return t.Format("2006-01-02")
+}
+
+// Range helpers for numeric qualifiers.
+func Gt(n int) string           { return ">" + strconv.Itoa(n) }
+func Gte(n int) string          { return ">=" + strconv.Itoa(n) }
+func Lt(n int) string           { return "<" + strconv.Itoa(n) }
+func Lte(n int) string          { return "<=" + strconv.Itoa(n) }
+func Between(lo, hi int) string { return strconv.Itoa(lo) + ".." + strconv.Itoa(hi) }
+
+// quote wraps s in double quotes when it contains spaces or characters
+// that would otherwise end the term or qualifier.
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\":()") {
+		// the search syntax has no escape for quotes, drop them
+		return `"` + strings.ReplaceAll(s, `"`, "") + `"`
+	}
+	return s
+}
+
+// SearchOptions are the query parameters shared by the search endpoints.
+type SearchOptions struct {
+	// Sort depends on the endpoint, e.g. "stars" for repositories or "followers" for users.
+	Sort string
+	// Order is "asc" or "desc".
+	Order string
+	// PerPage is the page size, GitHub caps it at 100.
+	PerPage int
+	// NoSplit stops the Search methods from splitting the created range of queries with
+	// more than 1000 results, only their first 1000 results are returned.
+	NoSplit bool
+}
+
+// SearchUser is an item of the users search.
+type SearchUser struct {
+	Login string  `json:"login"`
+	ID    int64   `json:"id"`
+	Type  string  `json:"type"`
+	Score float64 `json:"score"`
+}
+
+// Issue is an item of the issues search, pull requests included.
+type Issue struct {
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	State         string    `json:"state"`
+	HTMLURL       string    `json:"html_url"`
+	User          Account   `json:"user"`
+	CreatedAt     time.Time `json:"created_at"`
+	RepositoryURL string    `json:"repository_url"`
+	PullRequest   *struct{} `json:"pull_request,omitempty"`
+}
+
+// CodeResult is an item of the code search.
+type CodeResult struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	SHA        string `json:"sha"`
+	HTMLURL    string `json:"html_url"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// SearchUsers iterates over the users matching q, see ListRepos for how to call the iterator.
+func (c *Client) SearchUsers(ctx context.Context, q *Query, opts *SearchOptions) func(yield func(SearchUser, error) bool) {
+	return search[SearchUser](ctx, c, "users", q, opts)
+}
+
+// SearchRepos iterates over the repositories matching q.
+func (c *Client) SearchRepos(ctx context.Context, q *Query, opts *SearchOptions) func(yield func(Repo, error) bool) {
+	return search[Repo](ctx, c, "repositories", q, opts)
+}
+
+// SearchIssues iterates over the issues and pull requests matching q.
+func (c *Client) SearchIssues(ctx context.Context, q *Query, opts *SearchOptions) func(yield func(Issue, error) bool) {
+	return search[Issue](ctx, c, "issues", q, opts)
+}
+
+// SearchCode iterates over the files matching q. Code search has no created
+// qualifier, so only the first 1000 results can be reached.
+func (c *Client) SearchCode(ctx context.Context, q *Query, opts *SearchOptions) func(yield func(CodeResult, error) bool) {
+	return search[CodeResult](ctx, c, "code", q, opts)
+}
+
+type searchPage[T any] struct {
+	TotalCount        int  `json:"total_count"`
+	IncompleteResults bool `json:"incomplete_results"`
+	Items             []T  `json:"items"`
+}
+
+// search pages through /search/{kind}, stopping at GitHub's 1000 result window.
+// When the query has more results than that, its created range is halved
+// until every piece fits in the window, so all results are reached.
+// Results are then sorted within each piece only.
+func search[T any](ctx context.Context, c *Client, kind string, q *Query, opts *SearchOptions) func(yield func(T, error) bool) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	return func(yield func(T, error) bool) {
+		var zero T
+		if err := searchRange(ctx, c, kind, q, opts, yield); err != nil && err != errStop {
+			yield(zero, err)
+		}
+	}
+}
+
+// errStop unwinds searchRange when the caller's yield returned false.
+var errStop = errors.New("stop")
+
+func searchRange[T any](ctx context.Context, c *Client, kind string, q *Query, opts *SearchOptions, yield func(T, error) bool) error {
+	v := url.Values{"q": {q.String()}}
+	if opts.Sort != "" {
+		v.Set("sort", opts.Sort)
+	}
+	if opts.Order != "" {
+		v.Set("order", opts.Order)
+	}
+	if opts.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+
+	next, seen := "search/"+kind+"?"+v.Encode(), 0
+	for first := true; next != "" && seen < searchWindow; first = false {
+		req, err := c.NewRequest(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return err
+		}
+		var page searchPage[T]
+		resp, err := c.doJSON(req, &page)
+		if err != nil {
+			return err
+		}
+
+		if first && page.TotalCount > searchWindow && !opts.NoSplit && kind != "code" {
+			if from, mid, to, ok := splitRange(q.created); ok {
+				c.logger().InfoContext(ctx, "github search exceeds the result window, splitting",
+					"query", q.String(), "total_count", page.TotalCount, "split_at", mid.Format("2006-01-02"))
+				if err := searchRange(ctx, c, kind, q.withCreated(from, mid), opts, yield); err != nil {
+					return err
+				}
+				return searchRange(ctx, c, kind, q.withCreated(mid.AddDate(0, 0, 1), to), opts, yield)
+			}
+			c.logger().WarnContext(ctx, "github search exceeds the result window, results are truncated",
+				"query", q.String(), "total_count", page.TotalCount, "returned", searchWindow)
+		}
+
+		for _, it := range page.Items {
+			if seen >= searchWindow {
+				break
+			}
+			seen++
+			if !yield(it, nil) {
+				return errStop
+			}
+		}
+		next = nextLink(resp.Header)
+	}
+	return nil
+}
+
+// splitRange halves r by day. Open ends are closed with searchEpoch and today.
+// ok is false when the range is a single day and can't be split further.
+func splitRange(r *dateRange) (from, mid, to time.Time, ok bool) {
+	from, to = searchEpoch, time.Now().UTC().Truncate(24*time.Hour)
+	if r != nil && !r.from.IsZero() {
+		from = r.from
+	}
+	if r != nil && !r.to.IsZero() {
+		to = r.to
+	}
+
+	days := int(to.Sub(from).Hours() / 24)
+	if days < 1 {
+		return from, from, to, false
+	}
+	mid = from.AddDate(0, 0, (days-1)/2)
+	return from, mid, to, true
+}
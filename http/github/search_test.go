@@ -0,0 +1,162 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestQueryString(t *testing.T) {
+	for _, c := range []struct {
+		q    *Query
+		want string
+	}{
+		{NewQuery("http client").Language("go").Stars(Gt(100)).Org("golang"), `"http client" language:go stars:>100 org:golang`},
+		{NewQuery("retry").Repo("golang/go").Is("open"), `retry repo:golang/go is:open`},
+		{NewQuery().Qualifier("label", "good first issue"), `label:"good first issue"`},
+		{NewQuery(`say "hi"`), `"say hi"`},
+		{NewQuery("a:b", "(x)", ""), `"a:b" "(x)" ""`},
+		{NewQuery().Followers(Between(10, 20)).Stars(Lte(5)), `followers:10..20 stars:<=5`},
+		{NewQuery().User("alice").Stars(Gte(1)).Stars(Lt(9)), `user:alice stars:>=1 stars:<9`},
+		{NewQuery("x").Created(day("2020-01-01"), day("2020-12-31")), `x created:2020-01-01..2020-12-31`},
+		{NewQuery("x").Created(day("2020-01-01"), time.Time{}), `x created:2020-01-01..*`},
+		{NewQuery("x").Created(time.Time{}, day("2020-12-31")), `x created:*..2020-12-31`},
+	} {
+		if got := c.q.String(); got != c.want {
+			t.Errorf("got %s, want %s", got, c.want)
+		}
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	for _, c := range []struct {
+		from, to string
+		mid      string
+		ok       bool
+	}{
+		{"2020-01-01", "2020-01-04", "2020-01-02", true},
+		{"2020-01-01", "2020-01-05", "2020-01-02", true},
+		{"2020-01-01", "2020-01-02", "2020-01-01", true},
+		{"2020-01-01", "2020-01-01", "2020-01-01", false},
+	} {
+		_, mid, _, ok := splitRange(&dateRange{day(c.from), day(c.to)})
+		if ok != c.ok || !mid.Equal(day(c.mid)) {
+			t.Errorf("%s..%s: split at %s %v, want %s %v", c.from, c.to, mid.Format("2006-01-02"), ok, c.mid, c.ok)
+		}
+	}
+
+	from, _, to, ok := splitRange(nil)
+	if !ok || !from.Equal(searchEpoch) || time.Since(to) > 24*time.Hour {
+		t.Errorf("open range: %v..%v %v, want %v..today", from, to, ok, searchEpoch)
+	}
+}
+
+// searchServer answers /search/users from users created on the days of
+// created, honouring the created qualifier, pages and the 1000 result window.
+func searchServer(t *testing.T, created []time.Time) (*Client, *[]string) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mu.Lock()
+		queries = append(queries, q.Get("q"))
+		mu.Unlock()
+		from, to := time.Time{}, time.Now()
+		for _, f := range strings.Fields(q.Get("q")) {
+			if rng, ok := strings.CutPrefix(f, "created:"); ok {
+				lo, hi, _ := strings.Cut(rng, "..")
+				from, to = day(lo), day(hi)
+			}
+		}
+		var match []SearchUser
+		for i, c := range created {
+			if !c.Before(from) && !c.After(to) {
+				match = append(match, SearchUser{Login: fmt.Sprintf("u%d", i), ID: int64(i)})
+			}
+		}
+
+		page, _ := strconv.Atoi(q.Get("page"))
+		page = max(page, 1)
+		perPage, _ := strconv.Atoi(q.Get("per_page"))
+		lo, hi := min((page-1)*perPage, len(match), 1000), min(page*perPage, len(match), 1000)
+		if hi < min(len(match), 1000) {
+			q.Set("page", strconv.Itoa(page+1))
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?%s>; rel="next"`, r.Host, r.URL.Path, q.Encode()))
+		}
+		json.NewEncoder(w).Encode(searchPage[SearchUser]{TotalCount: len(match), Items: match[lo:hi]})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.BaseURL = srv.URL
+	c.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return c, &queries
+}
+
+// TestSearchSplit searches 1500 users created over January 2020: the 1000
+// result window makes the search split January in two halves of 750.
+func TestSearchSplit(t *testing.T) {
+	created := make([]time.Time, 1500)
+	for i := range created {
+		created[i] = day("2020-01-01").AddDate(0, 0, i%31)
+	}
+	c, queries := searchServer(t, created)
+
+	seen := make(map[string]bool)
+	q := NewQuery().Created(day("2020-01-01"), day("2020-01-31"))
+	c.SearchUsers(context.Background(), q, &SearchOptions{PerPage: 100})(func(u SearchUser, err error) bool {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[u.Login] {
+			t.Errorf("%s returned twice", u.Login)
+		}
+		seen[u.Login] = true
+		return true
+	})
+	if len(seen) != len(created) {
+		t.Errorf("%d users, want %d", len(seen), len(created))
+	}
+	want := []string{"created:2020-01-01..2020-01-31", "created:2020-01-01..2020-01-15", "created:2020-01-16..2020-01-31"}
+	for _, w := range want {
+		found := false
+		for _, got := range *queries {
+			found = found || got == w
+		}
+		if !found {
+			t.Errorf("no query %q in %q", w, *queries)
+		}
+	}
+
+	// without splitting, the window is all there is
+	n := 0
+	c.SearchUsers(context.Background(), q, &SearchOptions{PerPage: 100, NoSplit: true})(func(_ SearchUser, err error) bool {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+		return true
+	})
+	if n != 1000 {
+		t.Errorf("%d users without splitting, want 1000", n)
+	}
+}
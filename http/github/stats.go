@@ -0,0 +1,231 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// ErrStatsPending is returned when GitHub is still computing repository
+// statistics (202 Accepted) and the deadline passed before they were ready.
+var ErrStatsPending = errors.New("github: statistics are still being computed")
+
+// defaultStatsTimeout bounds the polling when ctx has no deadline.
+const defaultStatsTimeout = 2 * time.Minute
+
+// ContributorStats is one entry of /stats/contributors.
+type ContributorStats struct {
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Total int `json:"total"`
+	Weeks []struct {
+		Week      UnixTime `json:"w"`
+		Additions int      `json:"a"`
+		Deletions int      `json:"d"`
+		Commits   int      `json:"c"`
+	} `json:"weeks"`
+}
+
+// WeeklyCommits is one entry of /stats/commit_activity.
+type WeeklyCommits struct {
+	Week  UnixTime `json:"week"`
+	Total int      `json:"total"`
+	// Days are the commits of each day, starting on Sunday.
+	Days [7]int `json:"days"`
+}
+
+// WeeklyChanges is one entry of /stats/code_frequency.
+type WeeklyChanges struct {
+	Week      time.Time
+	Additions int
+	// Deletions is positive, GitHub sends it negated.
+	Deletions int
+}
+
+// UnmarshalJSON decodes the [week, additions, -deletions] triple GitHub sends.
+func (wc *WeeklyChanges) UnmarshalJSON(data []byte) error {
+	var v [3]int64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*wc = WeeklyChanges{Week: time.Unix(v[0], 0).UTC(), Additions: int(v[1]), Deletions: int(-v[2])}
+	return nil
+}
+
+// UnixTime is a time GitHub sends as seconds since the epoch.
+type UnixTime struct{ time.Time }
+
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	var s int64
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	t.Time = time.Unix(s, 0).UTC()
+	return nil
+}
+
+// ContributorStats returns the weekly additions, deletions and commits of
+// the top 100 contributors of owner/repo, waiting for GitHub to compute them.
+func (c *Client) ContributorStats(ctx context.Context, owner, repo string) ([]ContributorStats, error) {
+	var v []ContributorStats
+	err := c.pollStats(ctx, owner, repo, "contributors", &v)
+	return v, err
+}
+
+// CommitActivity returns the commits per week of the last year of owner/repo.
+func (c *Client) CommitActivity(ctx context.Context, owner, repo string) ([]WeeklyCommits, error) {
+	var v []WeeklyCommits
+	err := c.pollStats(ctx, owner, repo, "commit_activity", &v)
+	return v, err
+}
+
+// CodeFrequency returns the weekly additions and deletions of owner/repo.
+func (c *Client) CodeFrequency(ctx context.Context, owner, repo string) ([]WeeklyChanges, error) {
+	var v []WeeklyChanges
+	err := c.pollStats(ctx, owner, repo, "code_frequency", &v)
+	return v, err
+}
+
+// pollStats GETs /repos/{owner}/{repo}/stats/{kind} until it stops answering
+// 202 Accepted, backing off like c.Retry between polls, and decodes the result into v.
+// A 204 (empty repository) leaves v untouched.
+func (c *Client) pollStats(ctx context.Context, owner, repo, kind string, v any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultStatsTimeout)
+		defer cancel()
+	}
+	path := fmt.Sprintf("repos/%s/%s/stats/%s", url.PathEscape(owner), url.PathEscape(repo), kind)
+
+	for poll := 1; ; poll++ {
+		req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := c.do(req)
+		if err != nil {
+			return err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusAccepted:
+			io.Copy(io.Discard, c.limitBody(resp))
+			resp.Body.Close()
+		case http.StatusNoContent:
+			resp.Body.Close()
+			return nil
+		default:
+			defer resp.Body.Close()
+			return bodyErr(req, json.NewDecoder(c.limitBody(resp)).Decode(v))
+		}
+
+		wait := c.Retry.backoff(poll, time.Second)
+		c.logger().InfoContext(ctx, "github statistics not ready", "path", path, "poll", poll, "retry_in", wait)
+		if err := sleep(ctx, wait); err != nil {
+			return fmt.Errorf("%w: %s", ErrStatsPending, path)
+		}
+	}
+}
+
+// AuthorWeek is one author's activity during one week.
+type AuthorWeek struct {
+	Author    string
+	Week      time.Time
+	Additions int
+	Deletions int
+	Commits   int
+}
+
+// AuthorWeeks flattens contributor stats into per-author weekly rows,
+// leaving out the weeks without commits, ordered by week then author.
+func AuthorWeeks(stats []ContributorStats) []AuthorWeek {
+	var out []AuthorWeek
+	for _, s := range stats {
+		for _, w := range s.Weeks {
+			if w.Commits == 0 && w.Additions == 0 && w.Deletions == 0 {
+				continue
+			}
+			out = append(out, AuthorWeek{s.Author.Login, w.Week.Time, w.Additions, w.Deletions, w.Commits})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Week.Equal(out[j].Week) {
+			return out[i].Week.Before(out[j].Week)
+		}
+		return out[i].Author < out[j].Author
+	})
+	return out
+}
+
+// AuthorTotals sums AuthorWeeks rows per author, the Week of each total is
+// the author's last active week. Authors are ordered by commits, most first.
+func AuthorTotals(weeks []AuthorWeek) []AuthorWeek {
+	byAuthor := make(map[string]*AuthorWeek)
+	var out []*AuthorWeek
+	for _, w := range weeks {
+		t, ok := byAuthor[w.Author]
+		if !ok {
+			t = &AuthorWeek{Author: w.Author}
+			byAuthor[w.Author] = t
+			out = append(out, t)
+		}
+		t.Additions += w.Additions
+		t.Deletions += w.Deletions
+		t.Commits += w.Commits
+		if w.Week.After(t.Week) {
+			t.Week = w.Week
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Commits > out[j].Commits })
+	totals := make([]AuthorWeek, len(out))
+	for i, t := range out {
+		totals[i] = *t
+	}
+	return totals
+}
+
+// RepoWeek is the activity of a whole repository during one week.
+type RepoWeek struct {
+	Week      time.Time
+	Additions int
+	Deletions int
+	Commits   int
+}
+
+// RepoWeeks joins commit activity and code frequency on the week, ordered by week.
+func RepoWeeks(activity []WeeklyCommits, changes []WeeklyChanges) []RepoWeek {
+	byWeek := make(map[int64]*RepoWeek)
+	get := func(t time.Time) *RepoWeek {
+		w, ok := byWeek[t.Unix()]
+		if !ok {
+			w = &RepoWeek{Week: t}
+			byWeek[t.Unix()] = w
+		}
+		return w
+	}
+
+	for _, a := range activity {
+		get(a.Week.Time).Commits += a.Total
+	}
+	for _, c := range changes {
+		w := get(c.Week)
+		w.Additions += c.Additions
+		w.Deletions += c.Deletions
+	}
+
+	out := make([]RepoWeek, 0, len(byWeek))
+	for _, w := range byWeek {
+		out = append(out, *w)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Week.Before(out[j].Week) })
+	return out
+}
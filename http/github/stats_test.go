@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const contributorsBody = `[
+  {"author": {"login": "bob"}, "total": 3, "weeks": [
+    {"w": 1704585600, "a": 10, "d": 2, "c": 1},
+    {"w": 1705190400, "a": 0, "d": 0, "c": 0},
+    {"w": 1705795200, "a": 5, "d": 5, "c": 2}
+  ]},
+  {"author": {"login": "alice"}, "total": 4, "weeks": [
+    {"w": 1704585600, "a": 100, "d": 0, "c": 4},
+    {"w": 1705190400, "a": 0, "d": 0, "c": 0}
+  ]}
+]`
+
+// weeks of 2024 as GitHub sends them, starting on Sunday
+var week1, week2, week3 = time.Unix(1704585600, 0).UTC(), time.Unix(1705190400, 0).UTC(), time.Unix(1705795200, 0).UTC()
+
+// TestContributorStatsPolling takes two seconds: GitHub answers 202 while it
+// computes the statistics, and the polls are at least a second apart.
+func TestContributorStatsPolling(t *testing.T) {
+	accepted := reply{status: http.StatusAccepted, body: "{}"}
+	c, bodies := scripted(t, accepted, accepted, reply{status: http.StatusOK, body: contributorsBody})
+
+	stats, err := c.ContributorStats(context.Background(), "acme", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies()) != 3 {
+		t.Errorf("%d polls, want 3", len(bodies()))
+	}
+	if len(stats) != 2 || stats[0].Author.Login != "bob" || stats[1].Total != 4 || !stats[0].Weeks[2].Week.Equal(week3) {
+		t.Errorf("got %+v", stats)
+	}
+}
+
+func TestStatsEmptyRepository(t *testing.T) {
+	c, _ := scripted(t, reply{status: http.StatusNoContent})
+	activity, err := c.CommitActivity(context.Background(), "acme", "empty")
+	if err != nil || activity != nil {
+		t.Errorf("got %v, %v, want nothing", activity, err)
+	}
+}
+
+func TestStatsPending(t *testing.T) {
+	c, _ := scripted(t, reply{status: http.StatusAccepted})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.CodeFrequency(ctx, "acme", "app"); !errors.Is(err, ErrStatsPending) {
+		t.Errorf("got %v, want ErrStatsPending", err)
+	}
+}
+
+func TestStatsMaxBodyBytes(t *testing.T) {
+	c, _ := scripted(t, reply{status: http.StatusOK, body: contributorsBody})
+	c.MaxBodyBytes = 100
+	if _, err := c.ContributorStats(context.Background(), "acme", "app"); err == nil || !strings.Contains(err.Error(), "exceeds 100 bytes") {
+		t.Errorf("got %v, want the body limit error", err)
+	}
+}
+
+func TestCodeFrequencyDecode(t *testing.T) {
+	c, _ := scripted(t, reply{status: http.StatusOK, body: `[[1704585600, 120, -30], [1705190400, 0, 0]]`})
+	changes, err := c.CodeFrequency(context.Background(), "acme", "app")
+	want := []WeeklyChanges{{week1, 120, 30}, {week2, 0, 0}}
+	if err != nil || !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %v, %v, want %v", changes, err, want)
+	}
+}
+
+func TestAuthorWeeks(t *testing.T) {
+	c, _ := scripted(t, reply{status: http.StatusOK, body: contributorsBody})
+	stats, err := c.ContributorStats(context.Background(), "acme", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weeks := AuthorWeeks(stats)
+	want := []AuthorWeek{
+		{"alice", week1, 100, 0, 4},
+		{"bob", week1, 10, 2, 1},
+		{"bob", week3, 5, 5, 2},
+	}
+	if !reflect.DeepEqual(weeks, want) {
+		t.Errorf("AuthorWeeks = %v, want %v", weeks, want)
+	}
+
+	totals := AuthorTotals(weeks)
+	wantTotals := []AuthorWeek{
+		{"alice", week1, 100, 0, 4},
+		{"bob", week3, 15, 7, 3},
+	}
+	if !reflect.DeepEqual(totals, wantTotals) {
+		t.Errorf("AuthorTotals = %v, want %v", totals, wantTotals)
+	}
+}
+
+func TestRepoWeeks(t *testing.T) {
+	activity := []WeeklyCommits{{Week: UnixTime{week2}, Total: 7}, {Week: UnixTime{week1}, Total: 3}}
+	changes := []WeeklyChanges{{week1, 50, 10}, {week3, 1, 1}}
+	want := []RepoWeek{
+		{week1, 50, 10, 3},
+		{week2, 0, 0, 7},
+		{week3, 1, 1, 0},
+	}
+	if got := RepoWeeks(activity, changes); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
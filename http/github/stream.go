@@ -0,0 +1,174 @@
+package github
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// DecodeArray reads a JSON array from r and calls fn with each element as
+// soon as it is decoded, so only one element is in memory at a time.
+//
+// When T is a struct, object fields it has no json field for are skipped
+// token by token instead of being buffered, which keeps large unused fields
+// (e.g. the dozens of *_url members of every GitHub object) cheap.
+// Decoding stops at the first error returned by fn.
+func DecodeArray[T any](r io.Reader, fn func(T) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+
+	fields := jsonFields(reflect.TypeOf((*T)(nil)).Elem())
+	for dec.More() {
+		var v T
+		if err := decodeElement(dec, &v, fields); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// decodeElement decodes the next value into v, keeping only fields when
+// it is an object and fields is not nil.
+func decodeElement(dec *json.Decoder, v any, fields map[string]bool) error {
+	if fields == nil {
+		return dec.Decode(v)
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil { // null element, leave v zero
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("github: expected object, got %v", tok)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if !fields[strings.ToLower(key)] {
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(raw)
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return json.Unmarshal(buf.Bytes(), v)
+}
+
+// skipValue consumes the next value, however deeply nested, one token at a time.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("github: expected %v, got %v", want, tok)
+	}
+	return nil
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// jsonFields returns the lower cased JSON names encoding/json would decode
+// into t, or nil when t must be decoded whole: when it is not a struct, or
+// decodes itself like time.Time.
+func jsonFields(t reflect.Type) map[string]bool {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if pt := reflect.PointerTo(t); pt.Implements(jsonUnmarshaler) || pt.Implements(textUnmarshaler) {
+		return nil // the method set of *T includes the one of T
+	}
+	fields := make(map[string]bool)
+	addFields(t, fields)
+	return fields
+}
+
+func addFields(t reflect.Type, fields map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// untagged embedded structs have their fields promoted
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFields(ft, fields)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = true
+	}
+}
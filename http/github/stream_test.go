@@ -0,0 +1,93 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// collect decodes the array in s with DecodeArray.
+func collect[T any](s string) ([]T, error) {
+	var out []T
+	err := DecodeArray(strings.NewReader(s), func(v T) error {
+		out = append(out, v)
+		return nil
+	})
+	return out, err
+}
+
+func TestDecodeArraySkipsFields(t *testing.T) {
+	type repo struct {
+		Name  string `json:"name"`
+		Stars int    `json:"stargazers_count"`
+	}
+	got, err := collect[repo](`[
+		{"name": "api", "url": "https://x", "owner": {"login": "a", "urls": [1, [2]]}, "stargazers_count": 3},
+		null,
+		{"NAME": "web"}
+	]`)
+	want := []repo{{"api", 3}, {}, {"web", 0}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, %v, want %v", got, err, want)
+	}
+}
+
+// point decodes itself from [x, y], an array where DecodeArray would
+// expect the object of a struct.
+type point struct{ X, Y int }
+
+func (p *point) UnmarshalJSON(b []byte) error {
+	var xy [2]int
+	if err := json.Unmarshal(b, &xy); err != nil {
+		return err
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
+
+// level decodes itself from a string through encoding.TextUnmarshaler.
+type level struct{ n int }
+
+func (l *level) UnmarshalText(b []byte) error {
+	_, err := fmt.Sscanf(string(b), "L%d", &l.n)
+	return err
+}
+
+func TestDecodeArrayUnmarshalers(t *testing.T) {
+	points, err := collect[point](`[[1, 2], [3, 4]]`)
+	if want := []point{{1, 2}, {3, 4}}; err != nil || !reflect.DeepEqual(points, want) {
+		t.Errorf("points: got %v, %v, want %v", points, err, want)
+	}
+
+	levels, err := collect[level](`["L1", "L7"]`)
+	if want := []level{{1}, {7}}; err != nil || !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels: got %v, %v, want %v", levels, err, want)
+	}
+
+	times, err := collect[time.Time](`["2024-01-02T03:04:05Z"]`)
+	if err != nil || len(times) != 1 || !times[0].Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("times: got %v, %v", times, err)
+	}
+}
+
+func TestDecodeArrayErrors(t *testing.T) {
+	if _, err := collect[point](`[[1, 2]`); err == nil {
+		t.Error("unterminated array decoded")
+	}
+	if _, err := collect[int](`{}`); err == nil {
+		t.Error("object decoded as an array")
+	}
+	stop := errors.New("stop")
+	n := 0
+	err := DecodeArray(strings.NewReader(`[1, 2, 3]`), func(int) error {
+		n++
+		return stop
+	})
+	if !errors.Is(err, stop) || n != 1 {
+		t.Errorf("got %v after %d elements, want stop after 1", err, n)
+	}
+}
@@ -0,0 +1,346 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPayload is the largest delivery GitHub sends, bigger ones are dropped by GitHub.
+const maxPayload = 25 << 20
+
+// WebhookHandler is an http.Handler receiving GitHub webhook deliveries.
+// It checks the X-Hub-Signature-256 HMAC, decodes the payload according to
+// X-GitHub-Event and calls the handlers registered for that event.
+// Redeliveries of an already handled X-GitHub-Delivery ID are acknowledged
+// without calling the handlers again.
+//
+//	wh, err := github.NewWebhookHandler(os.Getenv("WEBHOOK_SECRET"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	wh.OnPush(func(ctx context.Context, e *github.PushEvent) error {
+//		slog.Info("push", "pusher", e.Pusher.Name, "commits", len(e.Commits), "ref", e.Ref)
+//		return nil
+//	})
+//	http.Handle("/webhook", wh)
+type WebhookHandler struct {
+	secret []byte
+
+	// Logger receives rejected deliveries and handler errors, slog.Default() when nil.
+	Logger *slog.Logger
+
+	mu       sync.Mutex
+	handlers map[string][]func(context.Context, any) error
+	// delivered remembers the last handled delivery IDs, inflight the ones being handled
+	delivered *lru
+	inflight  map[string]bool
+}
+
+// ErrEmptySecret is returned by NewWebhookHandler for an empty secret, with
+// which anyone can sign a delivery.
+var ErrEmptySecret = errors.New("github: empty webhook secret")
+
+// NewWebhookHandler returns a handler verifying deliveries against secret.
+// It remembers the last 10000 delivery IDs for deduplication.
+func NewWebhookHandler(secret string) (*WebhookHandler, error) {
+	if secret == "" {
+		return nil, ErrEmptySecret
+	}
+	return &WebhookHandler{
+		secret:    []byte(secret),
+		handlers:  make(map[string][]func(context.Context, any) error),
+		delivered: newLRU(10000),
+		inflight:  make(map[string]bool),
+	}, nil
+}
+
+// OnPush registers fn for "push" events.
+func (h *WebhookHandler) OnPush(fn func(context.Context, *PushEvent) error) {
+	h.on("push", func(ctx context.Context, e any) error { return fn(ctx, e.(*PushEvent)) })
+}
+
+// OnPullRequest registers fn for "pull_request" events.
+func (h *WebhookHandler) OnPullRequest(fn func(context.Context, *PullRequestEvent) error) {
+	h.on("pull_request", func(ctx context.Context, e any) error { return fn(ctx, e.(*PullRequestEvent)) })
+}
+
+// OnIssues registers fn for "issues" events.
+func (h *WebhookHandler) OnIssues(fn func(context.Context, *IssuesEvent) error) {
+	h.on("issues", func(ctx context.Context, e any) error { return fn(ctx, e.(*IssuesEvent)) })
+}
+
+// OnRelease registers fn for "release" events.
+func (h *WebhookHandler) OnRelease(fn func(context.Context, *ReleaseEvent) error) {
+	h.on("release", func(ctx context.Context, e any) error { return fn(ctx, e.(*ReleaseEvent)) })
+}
+
+func (h *WebhookHandler) on(event string, fn func(context.Context, any) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[event] = append(h.handlers[event], fn)
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayload))
+	if err != nil {
+		http.Error(w, "can't read body", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !VerifySignature(h.secret, body, r.Header.Get("X-Hub-Signature-256")) {
+		h.logger().Warn("github: webhook: bad signature", "delivery", r.Header.Get("X-GitHub-Delivery"))
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, id := r.Header.Get("X-GitHub-Event"), r.Header.Get("X-GitHub-Delivery")
+	if event == "ping" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	payload, err := decodeEvent(event, r.Header.Get("Content-Type"), body)
+	if errors.Is(err, errUnknownEvent) {
+		// still a 2xx, otherwise GitHub shows the delivery as failed
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		h.logger().Warn("github: webhook: bad payload", "delivery", id, "event", event, "error", err)
+		http.Error(w, "bad payload", http.StatusBadRequest)
+		return
+	}
+
+	if !h.begin(id) {
+		w.WriteHeader(http.StatusOK) // redelivery of something handled or being handled
+		return
+	}
+	err = h.dispatch(r.Context(), event, payload)
+	h.end(id, err == nil)
+	if err != nil {
+		h.logger().Error("github: webhook: handler failed", "delivery", id, "event", event, "error", err)
+		// a 5xx lets the delivery be redelivered, and it isn't marked as seen
+		http.Error(w, "handler failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// begin claims delivery id, it returns false if it was already handled or is in flight.
+// Deliveries without an ID are never deduplicated.
+func (h *WebhookHandler) begin(id string) bool {
+	if id == "" {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.inflight[id] || h.delivered.touch(id) {
+		return false
+	}
+	h.inflight[id] = true
+	return true
+}
+
+// end releases id, remembering it only when it was handled successfully.
+func (h *WebhookHandler) end(id string, ok bool) {
+	if id == "" {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.inflight, id)
+	if ok {
+		h.delivered.add(id, 1)
+	}
+}
+
+func (h *WebhookHandler) dispatch(ctx context.Context, event string, payload any) error {
+	h.mu.Lock()
+	fns := h.handlers[event]
+	h.mu.Unlock()
+
+	var errs []error
+	for _, fn := range fns {
+		if err := fn(ctx, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *WebhookHandler) logger() *slog.Logger {
+	if h.Logger != nil {
+		return h.Logger
+	}
+	return slog.Default()
+}
+
+// VerifySignature reports whether header, the value of X-Hub-Signature-256,
+// is the HMAC-SHA256 of body with secret. The comparison is constant time.
+func VerifySignature(secret, body []byte, header string) bool {
+	hexSig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	sig, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+var errUnknownEvent = errors.New("unknown event")
+
+// decodeEvent decodes body into the struct matching event.
+// Webhooks can be configured to send JSON or a form with a "payload" field.
+func decodeEvent(event, contentType string, body []byte) (any, error) {
+	var v any
+	switch event {
+	case "push":
+		v = &PushEvent{}
+	case "pull_request":
+		v = &PullRequestEvent{}
+	case "issues":
+		v = &IssuesEvent{}
+	case "release":
+		v = &ReleaseEvent{}
+	default:
+		return nil, errUnknownEvent
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(form.Get("payload"))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("%s payload: %w", event, err)
+	}
+	return v, nil
+}
+
+// Account is the user or organization found in event payloads.
+type Account struct {
+	Login string `json:"login"`
+	ID    int64  `json:"id"`
+	Type  string `json:"type"`
+}
+
+// EventRepo is the repository found in event payloads.
+type EventRepo struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	FullName string  `json:"full_name"`
+	Private  bool    `json:"private"`
+	HTMLURL  string  `json:"html_url"`
+	Owner    Account `json:"owner"`
+}
+
+// PushEvent is the payload of "push".
+type PushEvent struct {
+	Ref     string `json:"ref"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+	Created bool   `json:"created"`
+	Deleted bool   `json:"deleted"`
+	Forced  bool   `json:"forced"`
+	Commits []struct {
+		ID        string    `json:"id"`
+		Message   string    `json:"message"`
+		Timestamp time.Time `json:"timestamp"`
+		Author    struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"commits"`
+	Pusher struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"pusher"`
+	Repository EventRepo `json:"repository"`
+	Sender     Account   `json:"sender"`
+}
+
+// PullRequestEvent is the payload of "pull_request".
+type PullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title     string    `json:"title"`
+		State     string    `json:"state"`
+		Merged    bool      `json:"merged"`
+		Draft     bool      `json:"draft"`
+		HTMLURL   string    `json:"html_url"`
+		User      Account   `json:"user"`
+		CreatedAt time.Time `json:"created_at"`
+		Head      struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Repository EventRepo `json:"repository"`
+	Sender     Account   `json:"sender"`
+}
+
+// IssuesEvent is the payload of "issues".
+type IssuesEvent struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number int     `json:"number"`
+		Title  string  `json:"title"`
+		State  string  `json:"state"`
+		Body   string  `json:"body"`
+		User   Account `json:"user"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		HTMLURL string `json:"html_url"`
+	} `json:"issue"`
+	Repository EventRepo `json:"repository"`
+	Sender     Account   `json:"sender"`
+}
+
+// ReleaseEvent is the payload of "release".
+type ReleaseEvent struct {
+	Action  string `json:"action"`
+	Release struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Body        string    `json:"body"`
+		Draft       bool      `json:"draft"`
+		Prerelease  bool      `json:"prerelease"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+		Author      Account   `json:"author"`
+	} `json:"release"`
+	Repository EventRepo `json:"repository"`
+	Sender     Account   `json:"sender"`
+}
@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testSecret = "It's a Secret to Everybody"
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	io.WriteString(mac, body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	// the example of GitHub's "Validating webhook deliveries"
+	const body = "Hello, World!"
+	const want = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	if got := sign(testSecret, body); got != want {
+		t.Fatalf("sign = %s, want %s", got, want)
+	}
+	for _, c := range []struct {
+		name   string
+		header string
+		ok     bool
+	}{
+		{"good", want, true},
+		{"other secret", sign("other", body), false},
+		{"other body", sign(testSecret, body+"!"), false},
+		{"missing", "", false},
+		{"no prefix", strings.TrimPrefix(want, "sha256="), false},
+		{"sha1", "sha1=" + strings.TrimPrefix(want, "sha256="), false},
+		{"not hex", "sha256=zz", false},
+		{"truncated", want[:len(want)-2], false},
+	} {
+		if got := VerifySignature([]byte(testSecret), []byte(body), c.header); got != c.ok {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.ok)
+		}
+	}
+}
+
+func TestNewWebhookHandlerEmptySecret(t *testing.T) {
+	if _, err := NewWebhookHandler(""); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("got %v, want ErrEmptySecret", err)
+	}
+}
+
+// delivery is a webhook request as GitHub sends it.
+type delivery struct {
+	method      string
+	event, id   string
+	contentType string
+	body        string
+	signature   string // computed with testSecret when empty, "-" for none
+}
+
+func (d delivery) send(h http.Handler) int {
+	if d.method == "" {
+		d.method = http.MethodPost
+	}
+	if d.contentType == "" {
+		d.contentType = "application/json"
+	}
+	req := httptest.NewRequest(d.method, "/webhook", strings.NewReader(d.body))
+	req.Header.Set("Content-Type", d.contentType)
+	req.Header.Set("X-GitHub-Event", d.event)
+	req.Header.Set("X-GitHub-Delivery", d.id)
+	switch d.signature {
+	case "":
+		req.Header.Set("X-Hub-Signature-256", sign(testSecret, d.body))
+	case "-":
+	default:
+		req.Header.Set("X-Hub-Signature-256", d.signature)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+// newWebhook returns a handler counting the pushes, which fail while fail
+// is true.
+func newWebhook(t *testing.T) (h *WebhookHandler, pushes *[]string, fail *bool) {
+	h, err := NewWebhookHandler(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	pushes, fail = new([]string), new(bool)
+	h.OnPush(func(_ context.Context, e *PushEvent) error {
+		*pushes = append(*pushes, e.Ref)
+		if *fail {
+			return errors.New("database down")
+		}
+		return nil
+	})
+	return h, pushes, fail
+}
+
+const pushBody = `{"ref": "refs/heads/main", "pusher": {"name": "alice"}, "commits": [{"id": "abc"}]}`
+
+func TestWebhook(t *testing.T) {
+	form := url.Values{"payload": {pushBody}}.Encode()
+	for _, c := range []struct {
+		name   string
+		d      delivery
+		status int
+		pushes int
+	}{
+		{"push", delivery{event: "push", id: "1", body: pushBody}, http.StatusOK, 1},
+		{"form-encoded push", delivery{event: "push", id: "1", body: form, contentType: "application/x-www-form-urlencoded"}, http.StatusOK, 1},
+		{"bad signature", delivery{event: "push", id: "1", body: pushBody, signature: sign("guess", pushBody)}, http.StatusUnauthorized, 0},
+		{"missing signature", delivery{event: "push", id: "1", body: pushBody, signature: "-"}, http.StatusUnauthorized, 0},
+		{"GET", delivery{method: http.MethodGet, event: "push", id: "1"}, http.StatusMethodNotAllowed, 0},
+		{"ping", delivery{event: "ping", id: "1", body: `{"zen": "Keep it logically awesome."}`}, http.StatusOK, 0},
+		{"unknown event", delivery{event: "star", id: "1", body: `{"action": "created"}`}, http.StatusNoContent, 0},
+		{"bad payload", delivery{event: "push", id: "1", body: `{"ref": 1}`}, http.StatusBadRequest, 0},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			h, pushes, _ := newWebhook(t)
+			if status := c.d.send(h); status != c.status {
+				t.Errorf("status %d, want %d", status, c.status)
+			}
+			if len(*pushes) != c.pushes {
+				t.Errorf("%d pushes handled, want %d", len(*pushes), c.pushes)
+			}
+			if c.pushes > 0 && (*pushes)[0] != "refs/heads/main" {
+				t.Errorf("decoded ref %q", (*pushes)[0])
+			}
+		})
+	}
+}
+
+func TestWebhookRedelivery(t *testing.T) {
+	h, pushes, fail := newWebhook(t)
+	d := delivery{event: "push", id: "72d3162e", body: pushBody}
+
+	for i, want := range []struct {
+		fail   bool
+		status int
+		pushes int
+	}{
+		{true, http.StatusInternalServerError, 1},
+		{false, http.StatusOK, 2}, // a failed delivery isn't remembered
+		{false, http.StatusOK, 2}, // a handled one is
+	} {
+		*fail = want.fail
+		if status := d.send(h); status != want.status || len(*pushes) != want.pushes {
+			t.Errorf("delivery %d: status %d after %d pushes, want %d after %d", i+1, status, len(*pushes), want.status, want.pushes)
+		}
+	}
+
+	// deliveries without an ID are never deduplicated
+	d.id = ""
+	d.send(h)
+	d.send(h)
+	if len(*pushes) != 4 {
+		t.Errorf("%d pushes, want 4", len(*pushes))
+	}
+}
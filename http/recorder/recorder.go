@@ -0,0 +1,288 @@
+// Package recorder records HTTP interactions to a golden file and replays them,
+// so code talking to GitHub can run without network access.
+//
+//	rec, err := recorder.New("testdata/users.json", recorder.Replay)
+//	...
+//	c := github.NewClient()
+//	c.HTTPClient = &http.Client{Transport: rec}
+//
+// Run once in Record mode against the real API, call Save, commit the file,
+// and use Replay mode from then on.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder talks to the network.
+type Mode int
+
+const (
+	// Replay serves responses from the golden file and fails on unknown requests.
+	Replay Mode = iota
+	// Record forwards requests to the real server and remembers the exchanges.
+	Record
+)
+
+// Match selects which parts of a request must be equal for a recorded
+// interaction to be replayed.
+type Match struct {
+	Method bool
+	Path   bool
+	Query  bool
+	Body   bool
+}
+
+// DefaultMatch compares method, path and query.
+var DefaultMatch = Match{Method: true, Path: true, Query: true}
+
+// Redacted replaces the value of scrubbed headers.
+const Redacted = "REDACTED"
+
+// Interaction is one request/response pair of the golden file.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Like in Response, the body is kept as text
+// in Body when it is valid UTF-8, so the golden file stays readable, and in
+// BodyBytes otherwise (gzip, release assets...), which JSON encodes in base64.
+type Request struct {
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	Header    http.Header `json:"header,omitempty"`
+	Body      string      `json:"body,omitempty"`
+	BodyBytes []byte      `json:"body_bytes,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBytes  []byte      `json:"body_bytes,omitempty"`
+}
+
+// splitBody returns b as the Body or the BodyBytes of an interaction.
+func splitBody(b []byte) (string, []byte) {
+	if utf8.Valid(b) {
+		return string(b), nil
+	}
+	return "", b
+}
+
+// joinBody is the inverse of splitBody.
+func joinBody(text string, raw []byte) []byte {
+	if raw != nil {
+		return raw
+	}
+	return []byte(text)
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+type Recorder struct {
+	// Transport sends requests in Record mode, http.DefaultTransport when nil.
+	Transport http.RoundTripper
+	// Match decides which recorded interaction answers a request in Replay mode.
+	Match Match
+	// Scrub lists the headers whose values are never written to the golden file.
+	Scrub []string
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New returns a Recorder for the golden file at path.
+// In Replay mode the file is loaded right away and must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		Match: DefaultMatch,
+		Scrub: []string{"Authorization", "Cookie", "Set-Cookie"},
+		path:  path,
+		mode:  mode,
+	}
+	if mode == Record {
+		return r, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &r.interactions); err != nil {
+		return nil, fmt.Errorf("recorder: %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == Record {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// Save writes the recorded interactions to the golden file.
+// It is a no-op in Replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+// Unused returns the recorded interactions that were never replayed,
+// a sign that the code under test no longer makes a request it used to.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []Interaction
+	for i, ok := range r.used {
+		if !ok {
+			out = append(out, r.interactions[i])
+		}
+	}
+	return out
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	next := r.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.scrub(req.Header),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     r.scrub(resp.Header),
+		},
+	}
+	in.Request.Body, in.Request.BodyBytes = splitBody(body)
+	in.Response.Body, in.Response.BodyBytes = splitBody(respBody)
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers req with the first unused interaction that matches it,
+// so repeated identical requests are served in recording order.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || !r.matches(req, body, in.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		body := joinBody(in.Response.Body, in.Response.BodyBytes)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("recorder: no recorded interaction for %s %s in %s", req.Method, req.URL, r.path)
+}
+
+func (r *Recorder) matches(req *http.Request, body []byte, rec Request) bool {
+	u, err := url.Parse(rec.URL)
+	if err != nil {
+		return false
+	}
+
+	m := r.Match
+	switch {
+	case m.Method && req.Method != rec.Method:
+		return false
+	case m.Path && req.URL.Path != u.Path:
+		return false
+	// compare the parsed values so parameter order doesn't matter
+	case m.Query && req.URL.Query().Encode() != u.Query().Encode():
+		return false
+	case m.Body && !bytes.Equal(body, joinBody(rec.Body, rec.BodyBytes)):
+		return false
+	}
+	return true
+}
+
+func (r *Recorder) scrub(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range r.Scrub {
+		if h.Get(k) != "" {
+			h.Set(k, Redacted)
+		}
+	}
+	return h
+}
+
+// readBody reads the request body and returns a copy of req with the body
+// put back for the real transport. A RoundTripper must not modify req itself.
+func readBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return req, b, nil
+}
@@ -0,0 +1,146 @@
+package recorder
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/roundtrip.json")
+
+const fixture = "testdata/roundtrip.json"
+
+// asset starts like a gzip stream, it isn't valid UTF-8.
+var asset = []byte{0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 'v', '1'}
+
+// upload is a request body that isn't valid UTF-8 either.
+var upload = []byte{0xff, 0xfe, 0x00, 0x80}
+
+// server answers in process, so the recorded URLs don't depend on a port.
+type server struct{}
+
+func (server) RoundTrip(req *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	switch req.URL.Path {
+	case "/users/alice":
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"login":"alice","name":"Alice"}`)
+	case "/assets/1":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(asset)
+	case "/upload":
+		b, _ := io.ReadAll(req.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(b)
+	default:
+		http.NotFound(w, req)
+	}
+	resp := w.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// exchange makes the requests of the test through rt and checks the responses.
+func exchange(t *testing.T, rt http.RoundTripper) {
+	t.Helper()
+	c := &http.Client{Transport: rt}
+	get := func(url string, want []byte) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if got, _ := io.ReadAll(resp.Body); !bytes.Equal(got, want) {
+			t.Errorf("GET %s = %q, want %q", url, got, want)
+		}
+	}
+	get("https://api.github.com/users/alice", []byte(`{"login":"alice","name":"Alice"}`))
+	get("https://api.github.com/assets/1", asset)
+
+	resp, err := c.Post("https://api.github.com/upload", "application/octet-stream", bytes.NewReader(upload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, _ := io.ReadAll(resp.Body); resp.StatusCode != http.StatusCreated || !bytes.Equal(got, upload) {
+		t.Errorf("POST /upload = %d %q, want 201 %q", resp.StatusCode, got, upload)
+	}
+}
+
+// TestRoundTrip records the exchanges, compares the golden file with the
+// committed fixture, then replays the fixture.
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roundtrip.json")
+	if *update {
+		path = fixture
+	}
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Transport = server{}
+	exchange(t, rec)
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "secret") {
+		t.Error("the golden file holds the Authorization header")
+	}
+	want, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("recorded:\n%s\nwant %s (go test -update rewrites it):\n%s", got, fixture, want)
+	}
+
+	rep, err := New(fixture, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep.Match.Body = true
+	exchange(t, rep)
+	if u := rep.Unused(); len(u) != 0 {
+		t.Errorf("%d interactions not replayed", len(u))
+	}
+}
+
+func TestReplayUnknown(t *testing.T) {
+	rep, err := New(fixture, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &http.Client{Transport: rep}
+	if _, err := c.Get("https://api.github.com/users/bob"); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("got %v, want no recorded interaction", err)
+	}
+
+	// each interaction is replayed once
+	for i, want := range []int{http.StatusOK, 0} {
+		resp, err := c.Get("https://api.github.com/users/alice")
+		if want == 0 {
+			if err == nil {
+				t.Error("an interaction was replayed twice")
+			}
+			break
+		}
+		if err != nil || resp.StatusCode != want {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,393 @@
+// releases follows the releases of GitHub repositories: it prints READMEs and
+// release notes as plain text and downloads release assets, verifying them
+// against the digest GitHub computed or a checksum file of the release.
+//
+//	go run ./http/releases notes -n 3 golang/go cli/cli
+//	go run ./http/releases readme cli/cli
+//	go run ./http/releases download -tag v2.40.0 -match '*linux_amd64.tar.gz' -dir /tmp cli/cli
+//
+// GITHUB_TOKEN raises the rate limit, -base-url points the commands at a fake server.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"main/files/checksum"
+	"main/http/github"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+	ctx := context.Background()
+
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "readme":
+		fs := flag.NewFlagSet("readme", flag.ExitOnError)
+		ref := fs.String("ref", "", "branch, tag or commit, the default branch when empty")
+		markdown := fs.Bool("markdown", false, "print the Markdown instead of plain text")
+		c := newClient(fs, args)
+		if fs.NArg() != 1 {
+			usage()
+		}
+		owner, repo := splitRepo(fs.Arg(0))
+
+		md, err := c.ReadmeRaw(ctx, owner, repo, *ref)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		if !*markdown {
+			md = []byte(plainText(string(md)))
+		}
+		os.Stdout.Write(md)
+
+	case "notes":
+		fs := flag.NewFlagSet("notes", flag.ExitOnError)
+		n := fs.Int("n", 1, "number of releases per repository")
+		tag := fs.String("tag", "", "print only the release tagged `tag`")
+		pre := fs.Bool("pre", false, "include prereleases")
+		markdown := fs.Bool("markdown", false, "print the Markdown instead of plain text")
+		c := newClient(fs, args)
+		if fs.NArg() == 0 {
+			usage()
+		}
+		if *n < 1 {
+			log.Fatalf("error: -n %d: want at least 1 release", *n)
+		}
+
+		failed := false
+		for _, full := range fs.Args() {
+			owner, repo := splitRepo(full)
+			rels, err := releases(ctx, c, owner, repo, *tag, *n, *pre)
+			if err != nil {
+				log.Printf("error: %s: %v", full, err)
+				failed = true
+				continue
+			}
+			if len(rels) == 0 {
+				fmt.Printf("%s: no releases\n\n", full)
+			}
+			for _, r := range rels {
+				printNotes(os.Stdout, full, r, *markdown)
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+
+	case "download":
+		fs := flag.NewFlagSet("download", flag.ExitOnError)
+		tag := fs.String("tag", "", "release tag, the latest release when empty")
+		match := fs.String("match", "*", "download the assets whose name matches the `glob`")
+		dir := fs.String("dir", ".", "directory to download to")
+		strict := fs.Bool("strict", false, "fail on assets without a checksum instead of warning")
+		c := newClient(fs, args)
+		if fs.NArg() != 1 {
+			usage()
+		}
+		owner, repo := splitRepo(fs.Arg(0))
+
+		if err := download(ctx, c, owner, repo, *tag, *match, *dir, *strict); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: releases readme [-ref ref] [-markdown] owner/repo")
+	fmt.Fprintln(os.Stderr, "       releases notes [-n n] [-tag tag] [-pre] [-markdown] owner/repo...")
+	fmt.Fprintln(os.Stderr, "       releases download [-tag tag] [-match glob] [-dir dir] [-strict] owner/repo")
+	os.Exit(2)
+}
+
+// newClient adds the flags every command shares to fs, parses args and returns the client.
+func newClient(fs *flag.FlagSet, args []string) *github.Client {
+	baseURL := fs.String("base-url", github.DefaultBaseURL, "GitHub API root")
+	fs.Parse(args)
+
+	c := github.NewClient()
+	c.BaseURL = *baseURL
+	c.Token = os.Getenv("GITHUB_TOKEN")
+	return c
+}
+
+func splitRepo(full string) (owner, repo string) {
+	owner, repo, ok := strings.Cut(full, "/")
+	if !ok || owner == "" || repo == "" {
+		log.Fatalf("error: %q is not owner/repo", full)
+	}
+	return owner, repo
+}
+
+// releases returns the release tagged tag, or the n newest published releases.
+func releases(ctx context.Context, c *github.Client, owner, repo, tag string, n int, pre bool) ([]github.Release, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%d releases asked for, want at least 1", n)
+	}
+	if tag != "" {
+		r, err := c.ReleaseByTag(ctx, owner, repo, tag)
+		if err != nil {
+			return nil, err
+		}
+		return []github.Release{*r}, nil
+	}
+
+	var out []github.Release
+	var err error
+	c.ListReleases(ctx, owner, repo)(func(r github.Release, e error) bool {
+		if e != nil {
+			err = e
+			return false
+		}
+		if r.Draft || (r.Prerelease && !pre) {
+			return true
+		}
+		out = append(out, r)
+		return len(out) < n
+	})
+	return out, err
+}
+
+func printNotes(w io.Writer, full string, r github.Release, markdown bool) {
+	title := r.TagName
+	if r.Name != "" && r.Name != r.TagName {
+		title += " " + r.Name
+	}
+	if r.Prerelease {
+		title += " (prerelease)"
+	}
+	if !r.PublishedAt.IsZero() {
+		title += ", " + r.PublishedAt.Format("2006-01-02")
+	}
+	fmt.Fprintf(w, "%s %s\n%s\n\n", full, title, strings.Repeat("=", len(full)+1+len(title)))
+
+	body := r.Body
+	if !markdown {
+		body = plainText(body)
+	}
+	if body = strings.TrimSpace(body); body == "" {
+		body = "(no release notes)"
+	}
+	fmt.Fprintf(w, "%s\n\n", body)
+}
+
+// download fetches the assets of a release matching the glob into dir.
+// Each asset is written to a temporary file, verified, then renamed, so a
+// file with the asset's name is always complete and verified.
+func download(ctx context.Context, c *github.Client, owner, repo, tag, match, dir string, strict bool) error {
+	var (
+		r   *github.Release
+		err error
+	)
+	if tag == "" {
+		r, err = c.LatestRelease(ctx, owner, repo)
+	} else {
+		r, err = c.ReleaseByTag(ctx, owner, repo, tag)
+	}
+	if err != nil {
+		return err
+	}
+
+	sums, err := releaseSums(ctx, c, owner, repo, r)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, a := range r.Assets {
+		if ok, err := filepath.Match(match, a.Name); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		found = true
+
+		want := sums[a.Name]
+		if d, ok := strings.CutPrefix(a.Digest, "sha256:"); ok {
+			want = d
+		}
+		if want == "" && !isSumsFile(a.Name) {
+			if strict {
+				return fmt.Errorf("%s: no checksum to verify it with", a.Name)
+			}
+			log.Printf("warning: %s: no checksum, not verified", a.Name)
+		}
+
+		path, err := fetchAsset(ctx, c, owner, repo, a, dir, want)
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	if !found {
+		return fmt.Errorf("%s: no asset matches %q", r.TagName, match)
+	}
+	return nil
+}
+
+func fetchAsset(ctx context.Context, c *github.Client, owner, repo string, a github.Asset, dir, want string) (string, error) {
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name()) // fails once renamed
+
+	_, err = c.DownloadAsset(ctx, owner, repo, a.ID, tmp)
+	if err == nil && want != "" {
+		if _, err = tmp.Seek(0, io.SeekStart); err == nil {
+			err = checksum.Verify(tmp, want)
+		}
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", a.Name, err)
+	}
+
+	path := filepath.Join(dir, filepath.Base(a.Name))
+	return path, os.Rename(tmp.Name(), path)
+}
+
+// releaseSums downloads and merges the checksum files attached to r, if any.
+func releaseSums(ctx context.Context, c *github.Client, owner, repo string, r *github.Release) (map[string]string, error) {
+	sums := make(map[string]string)
+	for _, a := range r.Assets {
+		if !isSumsFile(a.Name) {
+			continue
+		}
+		var buf bytes.Buffer
+		if _, err := c.DownloadAsset(ctx, owner, repo, a.ID, &buf); err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Name, err)
+		}
+
+		// foo.tar.gz.sha256 often holds only the sum, without a file name
+		if base, ok := strings.CutSuffix(a.Name, ".sha256"); ok && !strings.Contains(strings.TrimSpace(buf.String()), " ") {
+			sums[base] = strings.ToLower(strings.TrimSpace(buf.String()))
+			continue
+		}
+		s, err := checksum.ParseSums(&buf)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Name, err)
+		}
+		for name, sum := range s {
+			sums[name] = sum
+		}
+	}
+	return sums, nil
+}
+
+// isSumsFile reports whether an asset looks like a checksum file:
+// checksums.txt, SHA256SUMS, foo_checksums.txt, foo.tar.gz.sha256...
+func isSumsFile(name string) bool {
+	n := strings.ToLower(name)
+	return strings.Contains(n, "checksums") || strings.HasPrefix(n, "sha256sums") ||
+		strings.HasPrefix(n, "sha512sums") || strings.HasSuffix(n, ".sha256")
+}
+
+var (
+	htmlComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	fence        = regexp.MustCompile("^\\s*(```|~~~)")
+	heading      = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)[\s#]*$`)
+	rule         = regexp.MustCompile(`^\s{0,3}((-\s*){3,}|(\*\s*){3,}|(_\s*){3,}|(=\s*){3,})$`)
+	quote        = regexp.MustCompile(`^\s*>\s?`)
+	bullet       = regexp.MustCompile(`^(\s*)[*+-]\s+`)
+	refDef       = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s+\S+`)
+	tableRule    = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*$`)
+	codeSpan     = regexp.MustCompile("`+([^`]+)`+")
+	image        = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	link         = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	refLink      = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	autolink     = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	htmlTag      = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	strong       = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	emphasis     = regexp.MustCompile(`\*([^*\s][^*]*?)\*|\b_([^_\s][^_]*?)_\b`)
+	strike       = regexp.MustCompile(`~~(.+?)~~`)
+	escaped      = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!>~|<])`)
+	blankLines   = regexp.MustCompile(`\n{3,}`)
+	markdownChar = "\\`*_{}[]()#+-.!>~|<"
+)
+
+// plainText strips the Markdown of release notes and READMEs: headings, emphasis,
+// links (their text is kept), images, HTML, quotes and code fences. It handles
+// what release notes use, not all of CommonMark.
+func plainText(md string) string {
+	md = htmlComment.ReplaceAllString(strings.ReplaceAll(md, "\r\n", "\n"), "")
+
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(md, "\n") {
+		if fence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, "    "+line)
+			continue
+		}
+		if rule.MatchString(line) || refDef.MatchString(line) || tableRule.MatchString(line) {
+			continue
+		}
+		if m := heading.FindStringSubmatch(line); m != nil {
+			out = append(out, inline(m[1]))
+			continue
+		}
+		line = quote.ReplaceAllString(line, "")
+		line = bullet.ReplaceAllString(line, "$1- ")
+		if t := strings.TrimSpace(line); strings.HasPrefix(t, "|") && strings.HasSuffix(t, "|") {
+			line = strings.Join(strings.Split(strings.Trim(t, "|"), "|"), "  ")
+		}
+		out = append(out, strings.TrimRight(inline(line), " \t"))
+	}
+	return blankLines.ReplaceAllString(strings.Join(out, "\n"), "\n\n")
+}
+
+// inline strips the Markdown within a line. Escaped characters and code spans
+// are hidden from the other rules by shifting them into the Private Use Area.
+func inline(s string) string {
+	s = escaped.ReplaceAllStringFunc(s, func(m string) string { return protect(m[1:]) })
+	s = codeSpan.ReplaceAllStringFunc(s, func(m string) string {
+		return protect(codeSpan.FindStringSubmatch(m)[1])
+	})
+
+	s = image.ReplaceAllString(s, "$1")
+	s = link.ReplaceAllString(s, "$1")
+	s = refLink.ReplaceAllString(s, "$1")
+	s = autolink.ReplaceAllString(s, "$1")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = strong.ReplaceAllString(s, "$2")
+	s = emphasis.ReplaceAllString(s, "$1$2")
+	s = strike.ReplaceAllString(s, "$1")
+
+	return strings.Map(func(r rune) rune {
+		if r >= privateUse && r < privateUse+128 {
+			return r - privateUse
+		}
+		return r
+	}, s)
+}
+
+const privateUse = 0xE000
+
+func protect(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(markdownChar, r) {
+			return privateUse + r
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"main/http/fakegithub"
+	"main/http/github"
+)
+
+func TestPlainText(t *testing.T) {
+	for _, c := range []struct{ md, want string }{
+		{"# Title #\n\nSome **bold**, *em*, _under_ and ~~gone~~ text.", "Title\n\nSome bold, em, under and gone text."},
+		{"Intro\n\n---\n\n* one\n- two\n  + nested", "Intro\n\n- one\n- two\n  - nested"},
+		// a lone - or * is an empty list item, not a rule
+		{"-\n*\n- item", "-\n*\n- item"},
+		{"* * *\n___\n===\n==", "=="},
+		{"[link](https://x.y) ![img](a.png) [ref][1] <https://go.dev>\n\n[1]: https://example.com", "link img ref https://go.dev\n"},
+		{"```go\nfmt.Println(\"*x*\")\n```\nafter", "    fmt.Println(\"*x*\")\nafter"},
+		{"> quoted **text**\n<!-- hidden -->\n<b>html</b>", "quoted text\n\nhtml"},
+		{"| a | b |\n|---|:-:|\n| 1 | 2 |", " a    b\n 1    2"},
+		{"\\*not em\\* and `*code*` and snake_case_name", "*not em* and *code* and snake_case_name"},
+		{"a\r\n\r\n\r\n\r\nb", "a\n\nb"},
+	} {
+		if got := plainText(c.md); got != c.want {
+			t.Errorf("plainText(%q)\n got %q\nwant %q", c.md, got, c.want)
+		}
+	}
+}
+
+func TestIsSumsFile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"checksums.txt":                 true,
+		"gh_2.40.0_checksums.txt":       true,
+		"SHA256SUMS":                    true,
+		"sha512sums.txt":                true,
+		"app_linux_amd64.tar.gz.sha256": true,
+		"app_linux_amd64.tar.gz":        false,
+		"SHA256SUMS.sig":                true,
+		"sha256.go":                     false,
+		"README.md":                     false,
+	} {
+		if got := isSumsFile(name); got != want {
+			t.Errorf("isSumsFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestReleases(t *testing.T) {
+	srv := httptest.NewServer(fakegithub.New(&fakegithub.Seed{
+		Users: []map[string]any{{"login": "alice"}},
+		Releases: map[string][]map[string]any{"alice/app": {
+			{"tag_name": "v4.0.0", "draft": true},
+			{"tag_name": "v3.0.0-rc1", "prerelease": true},
+			{"tag_name": "v2.0.0"},
+			{"tag_name": "v1.0.0"},
+		}},
+	}))
+	defer srv.Close()
+	c := github.NewClient()
+	c.BaseURL = srv.URL
+
+	for _, tc := range []struct {
+		n    int
+		pre  bool
+		tag  string
+		want []string
+	}{
+		{1, false, "", []string{"v2.0.0"}},
+		{2, false, "", []string{"v2.0.0", "v1.0.0"}},
+		{2, true, "", []string{"v3.0.0-rc1", "v2.0.0"}},
+		{10, false, "", []string{"v2.0.0", "v1.0.0"}},
+		{1, false, "v1.0.0", []string{"v1.0.0"}},
+	} {
+		rels, err := releases(context.Background(), c, "alice", "app", tc.tag, tc.n, tc.pre)
+		var got []string
+		for _, r := range rels {
+			got = append(got, r.TagName)
+		}
+		if err != nil || !slices.Equal(got, tc.want) {
+			t.Errorf("n %d pre %v tag %q: got %v, %v, want %v", tc.n, tc.pre, tc.tag, got, err, tc.want)
+		}
+	}
+
+	for _, n := range []int{0, -1} {
+		if rels, err := releases(context.Background(), c, "alice", "app", "", n, false); err == nil {
+			t.Errorf("n %d: got %d releases, want an error", n, len(rels))
+		}
+	}
+}
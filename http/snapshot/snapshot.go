@@ -0,0 +1,292 @@
+// snapshot records GitHub users or organizations to timestamped JSON files
+// and shows what changed between two of them.
+//
+//	go run ./http/snapshot take -dir snapshots golang Jesserc
+//	go run ./http/snapshot diff -dir snapshots                    # the two newest snapshots
+//	go run ./http/snapshot diff snapshots/a.json snapshots/b.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"main/http/github"
+)
+
+// Snapshot is the content of one snapshot file.
+type Snapshot struct {
+	TakenAt  time.Time  `json:"taken_at"`
+	Accounts []*Account `json:"accounts"`
+}
+
+// Account is what we track of a user or an organization.
+type Account struct {
+	Login       string   `json:"login"`
+	Name        string   `json:"name"`
+	PublicRepos int      `json:"public_repos"`
+	Stars       int      `json:"stars"`
+	Followers   []string `json:"followers"`
+	Repos       []Repo   `json:"repos"`
+}
+
+type Repo struct {
+	Name     string `json:"name"`
+	Stars    int    `json:"stars"`
+	Forks    int    `json:"forks"`
+	Archived bool   `json:"archived,omitempty"`
+}
+
+// fileLayout names snapshot files so they sort chronologically. The fixed
+// width nanoseconds keep two snapshots taken in the same second apart.
+const fileLayout = "2006-01-02T15-04-05.000000000Z.json"
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "take":
+		fs := flag.NewFlagSet("take", flag.ExitOnError)
+		dir := fs.String("dir", "snapshots", "directory of the snapshot files")
+		baseURL := fs.String("base-url", github.DefaultBaseURL, "GitHub API root")
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			usage()
+		}
+
+		c := github.NewClient()
+		c.BaseURL = *baseURL
+		c.Token = os.Getenv("GITHUB_TOKEN")
+		path, err := take(context.Background(), c, *dir, fs.Args())
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		fmt.Println(path)
+
+	case "diff":
+		fs := flag.NewFlagSet("diff", flag.ExitOnError)
+		dir := fs.String("dir", "snapshots", "directory to pick the two newest snapshots from")
+		fs.Parse(args)
+
+		paths := fs.Args()
+		if len(paths) == 0 {
+			var err error
+			if paths, err = newest(*dir, 2); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+		}
+		if len(paths) != 2 {
+			usage()
+		}
+
+		old, err := load(paths[0])
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		cur, err := load(paths[1])
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		diff(os.Stdout, old, cur)
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: snapshot take [-dir dir] login...")
+	fmt.Fprintln(os.Stderr, "       snapshot diff [-dir dir] [old.json new.json]")
+	os.Exit(2)
+}
+
+// take fetches logins and writes them to a new file in dir.
+func take(ctx context.Context, c *github.Client, dir string, logins []string) (string, error) {
+	s := Snapshot{TakenAt: time.Now().UTC()}
+	for _, login := range logins {
+		a, err := fetch(ctx, c, login)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", login, err)
+		}
+		s.Accounts = append(s.Accounts, a)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, s.TakenAt.Format(fileLayout))
+	// never overwrite a snapshot, should the clock go back
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+func fetch(ctx context.Context, c *github.Client, login string) (*Account, error) {
+	u, err := c.User(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{Login: u.Login, Name: u.Name, PublicRepos: u.NumOfRepos}
+
+	repos, err := c.CollectRepos(ctx, login, &github.ListReposOptions{PerPage: 100})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range repos {
+		a.Repos = append(a.Repos, Repo{r.Name, r.Stars, r.Forks, r.Archived})
+		a.Stars += r.Stars
+	}
+	sort.Slice(a.Repos, func(i, j int) bool { return a.Repos[i].Name < a.Repos[j].Name })
+
+	var ferr error
+	c.ListFollowers(ctx, login)(func(f github.Account, err error) bool {
+		if err != nil {
+			ferr = err
+			return false
+		}
+		a.Followers = append(a.Followers, f.Login)
+		return true
+	})
+	sort.Strings(a.Followers)
+	return a, ferr
+}
+
+// newest returns the n most recent snapshot files of dir, oldest first.
+func newest(dir string, n int) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) < n {
+		return nil, fmt.Errorf("%s: need %d snapshots, found %d", dir, n, len(paths))
+	}
+	sort.Strings(paths) // the names are timestamps
+	return paths[len(paths)-n:], nil
+}
+
+func load(path string) (*Snapshot, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// diff prints what changed for every account of old and cur.
+func diff(w io.Writer, old, cur *Snapshot) {
+	fmt.Fprintf(w, "%s -> %s (%v)\n", old.TakenAt.Format(time.RFC3339), cur.TakenAt.Format(time.RFC3339), cur.TakenAt.Sub(old.TakenAt).Round(time.Minute))
+
+	before := make(map[string]*Account)
+	for _, a := range old.Accounts {
+		before[strings.ToLower(a.Login)] = a
+	}
+
+	for _, a := range cur.Accounts {
+		fmt.Fprintf(w, "\n%s\n", a.Login)
+		b, ok := before[strings.ToLower(a.Login)]
+		if !ok {
+			fmt.Fprintf(w, "  not in the old snapshot: %d repos, %d stars, %d followers\n", len(a.Repos), a.Stars, len(a.Followers))
+			continue
+		}
+		delete(before, strings.ToLower(a.Login))
+		diffAccount(w, b, a)
+	}
+	for _, b := range old.Accounts {
+		if _, gone := before[strings.ToLower(b.Login)]; gone {
+			fmt.Fprintf(w, "\n%s\n  not in the new snapshot\n", b.Login)
+		}
+	}
+}
+
+func diffAccount(w io.Writer, old, cur *Account) {
+	fmt.Fprintf(w, "  stars:     %d -> %d (%+d)\n", old.Stars, cur.Stars, cur.Stars-old.Stars)
+	fmt.Fprintf(w, "  followers: %d -> %d (%+d)\n", len(old.Followers), len(cur.Followers), len(cur.Followers)-len(old.Followers))
+
+	gained, lost := setDiff(old.Followers, cur.Followers)
+	for _, f := range gained {
+		fmt.Fprintf(w, "    + %s\n", f)
+	}
+	for _, f := range lost {
+		fmt.Fprintf(w, "    - %s\n", f)
+	}
+
+	oldRepos := make(map[string]Repo)
+	var oldNames, curNames []string
+	for _, r := range old.Repos {
+		oldRepos[r.Name] = r
+		oldNames = append(oldNames, r.Name)
+	}
+	for _, r := range cur.Repos {
+		curNames = append(curNames, r.Name)
+	}
+
+	added, deleted := setDiff(oldNames, curNames)
+	for _, name := range added {
+		fmt.Fprintf(w, "  new repo:     %s\n", name)
+	}
+	for _, name := range deleted {
+		fmt.Fprintf(w, "  deleted repo: %s\n", name)
+	}
+
+	for _, r := range cur.Repos {
+		o, ok := oldRepos[r.Name]
+		if !ok {
+			continue
+		}
+		if d := r.Stars - o.Stars; d != 0 {
+			fmt.Fprintf(w, "  %-30s stars %d -> %d (%+d)\n", r.Name, o.Stars, r.Stars, d)
+		}
+		if r.Archived && !o.Archived {
+			fmt.Fprintf(w, "  %-30s archived\n", r.Name)
+		}
+	}
+}
+
+// setDiff returns the elements only in cur, and the ones only in old.
+func setDiff(old, cur []string) (added, removed []string) {
+	in := func(s []string) map[string]bool {
+		m := make(map[string]bool, len(s))
+		for _, v := range s {
+			m[v] = true
+		}
+		return m
+	}
+	o, c := in(old), in(cur)
+
+	for _, v := range cur {
+		if !o[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range old {
+		if !c[v] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"main/http/fakegithub"
+	"main/http/github"
+)
+
+func TestSetDiff(t *testing.T) {
+	for _, c := range []struct {
+		old, cur       []string
+		added, removed []string
+	}{
+		{nil, nil, nil, nil},
+		{nil, []string{"a", "b"}, []string{"a", "b"}, nil},
+		{[]string{"a", "b"}, nil, nil, []string{"a", "b"}},
+		{[]string{"a", "b", "c"}, []string{"b", "d", "a"}, []string{"d"}, []string{"c"}},
+		{[]string{"a", "a"}, []string{"a"}, nil, nil},
+	} {
+		added, removed := setDiff(c.old, c.cur)
+		if !reflect.DeepEqual(added, c.added) || !reflect.DeepEqual(removed, c.removed) {
+			t.Errorf("setDiff(%q, %q) = %q, %q, want %q, %q", c.old, c.cur, added, removed, c.added, c.removed)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	old := &Snapshot{TakenAt: t0, Accounts: []*Account{
+		{Login: "alice", Stars: 10, Followers: []string{"bob", "carol"}, Repos: []Repo{
+			{Name: "api", Stars: 7},
+			{Name: "old", Stars: 3},
+			{Name: "tool"},
+		}},
+		{Login: "gone"},
+	}}
+	cur := &Snapshot{TakenAt: t0.Add(26*time.Hour + 20*time.Second), Accounts: []*Account{
+		{Login: "Alice", Stars: 12, Followers: []string{"carol", "dave", "erin"}, Repos: []Repo{
+			{Name: "api", Stars: 9},
+			{Name: "new", Stars: 3},
+			{Name: "tool", Archived: true},
+		}},
+		{Login: "zed", Stars: 5, Followers: []string{"alice"}, Repos: []Repo{{Name: "z", Stars: 5}}},
+	}}
+
+	var b strings.Builder
+	diff(&b, old, cur)
+	want := `2024-05-01T12:00:00Z -> 2024-05-02T14:00:20Z (26h0m0s)
+
+Alice
+  stars:     10 -> 12 (+2)
+  followers: 2 -> 3 (+1)
+    + dave
+    + erin
+    - bob
+  new repo:     new
+  deleted repo: old
+  api                            stars 7 -> 9 (+2)
+  tool                           archived
+
+zed
+  not in the old snapshot: 1 repos, 5 stars, 1 followers
+
+gone
+  not in the new snapshot
+`
+	if b.String() != want {
+		t.Errorf("diff:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+// TestTake checks two snapshots taken in a row get their own files, found
+// in order by newest.
+func TestTake(t *testing.T) {
+	srv := httptest.NewServer(fakegithub.New(&fakegithub.Seed{
+		Users:     []map[string]any{{"login": "alice", "name": "Alice"}, {"login": "bob"}},
+		Repos:     map[string][]map[string]any{"alice": {{"name": "b", "stargazers_count": 2}, {"name": "a", "stargazers_count": 1}}},
+		Followers: map[string][]string{"alice": {"bob"}},
+	}))
+	defer srv.Close()
+	c := github.NewClient()
+	c.BaseURL = srv.URL
+
+	dir := t.TempDir()
+	first, err := take(context.Background(), c, dir, []string{"alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := take(context.Background(), c, dir, []string{"alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("both snapshots were written to %s", first)
+	}
+
+	paths, err := newest(dir, 2)
+	if err != nil || !reflect.DeepEqual(paths, []string{first, second}) {
+		t.Fatalf("newest = %q, %v, want %q", paths, err, []string{first, second})
+	}
+	s, err := load(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Account{
+		Login: "alice", Name: "Alice", PublicRepos: 2, Stars: 3, Followers: []string{"bob"},
+		Repos: []Repo{{Name: "a", Stars: 1}, {Name: "b", Stars: 2}},
+	}
+	if len(s.Accounts) != 1 {
+		t.Fatalf("%d accounts, want 1", len(s.Accounts))
+	}
+	if !reflect.DeepEqual(s.Accounts[0], want) {
+		t.Errorf("account = %+v, want %+v", s.Accounts[0], want)
+	}
+
+	if _, err := newest(filepath.Join(dir, "none"), 1); err == nil {
+		t.Error("newest of an empty directory succeeded")
+	}
+}
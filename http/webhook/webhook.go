@@ -0,0 +1,46 @@
+// webhook receives GitHub webhook deliveries and logs them.
+//
+//	WEBHOOK_SECRET=s3cret go run ./http/webhook -addr :8080
+//
+// Point the repository's webhook at http://<host>:8080/webhook with the same secret.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"main/http/github"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.Parse()
+
+	wh, err := github.NewWebhookHandler(os.Getenv("WEBHOOK_SECRET"))
+	if err != nil {
+		log.Fatal("error: WEBHOOK_SECRET: ", err)
+	}
+	wh.OnPush(func(ctx context.Context, e *github.PushEvent) error {
+		log.Printf("push: %s pushed %d commit(s) to %s of %s", e.Pusher.Name, len(e.Commits), e.Ref, e.Repository.FullName)
+		return nil
+	})
+	wh.OnPullRequest(func(ctx context.Context, e *github.PullRequestEvent) error {
+		log.Printf("pull_request: #%d %s %q by %s", e.Number, e.Action, e.PullRequest.Title, e.PullRequest.User.Login)
+		return nil
+	})
+	wh.OnIssues(func(ctx context.Context, e *github.IssuesEvent) error {
+		log.Printf("issues: #%d %s %q", e.Issue.Number, e.Action, e.Issue.Title)
+		return nil
+	})
+	wh.OnRelease(func(ctx context.Context, e *github.ReleaseEvent) error {
+		log.Printf("release: %s %s of %s", e.Action, e.Release.TagName, e.Repository.FullName)
+		return nil
+	})
+
+	http.Handle("/webhook", wh)
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -0,0 +1,236 @@
+// Package alias finds the slices that share a backing array, which is how
+// sl3 := sl2[3:5] in slices/slices.go sees and can overwrite the elements of
+// sl2, and how a small slice of a big buffer keeps the whole buffer alive.
+//
+// It compares the data pointers of the slices, so it only knows about the
+// memory the given slices can reach: two slices cut from one array with
+// full-slice expressions so that neither reaches the other are reported as
+// separate, which they are as far as their elements go.
+package alias
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"unsafe"
+
+	"main/slices/table"
+)
+
+// Span is the memory a slice can reach: Cap elements of Size bytes from Data,
+// the first Len of them visible.
+type Span struct {
+	Name     string
+	Data     uintptr
+	Len, Cap int
+	Size     uintptr
+
+	ref any // keeps the backing array alive, and at Data, while the Span is used
+}
+
+// Of records where s lies in memory.
+func Of[T any](name string, s []T) Span {
+	var zero T
+	return Span{
+		Name: name,
+		Data: uintptr(unsafe.Pointer(unsafe.SliceData(s))),
+		Len:  len(s),
+		Cap:  cap(s),
+		Size: unsafe.Sizeof(zero),
+		ref:  s,
+	}
+}
+
+// end returns the address past the first n elements of s.
+func (s Span) end(n int) uintptr {
+	return s.Data + uintptr(n)*s.Size
+}
+
+// Tail is the number of bytes past the length of s, reachable by reslicing
+// s up to its capacity and overwritten by append.
+func (s Span) Tail() uintptr {
+	return uintptr(s.Cap-s.Len) * s.Size
+}
+
+// empty reports whether s can't reach any memory, a zero capacity or
+// zero-size elements, which may all share one address.
+func (s Span) empty() bool {
+	return s.Cap == 0 || s.Size == 0
+}
+
+// Shares reports whether a and b can reach a common element.
+func Shares[T, U any](a []T, b []U) bool {
+	sa, sb := Of("", a), Of("", b)
+	if sa.empty() || sb.empty() {
+		return false
+	}
+	return sa.Data < sb.end(sb.Cap) && sb.Data < sa.end(sa.Cap)
+}
+
+// Detach returns a copy of s with no spare capacity, which shares nothing
+// with s: appending to it can't overwrite the neighbours of s and it doesn't
+// keep the backing array of s alive.
+func Detach[T any](s []T) []T {
+	if len(s) == 0 {
+		if s == nil {
+			return nil
+		}
+		return []T{} // s[:0:0] would still point into the array
+	}
+	return append(s[:0:0], s...)
+}
+
+// Kind is how two slices overlap.
+type Kind int
+
+const (
+	// Shared elements are visible in both slices, a write through one is
+	// seen by the other.
+	Shared Kind = iota
+	// Clobber elements are past the length of A but visible in B, appending
+	// to A overwrites them.
+	Clobber
+)
+
+// Overlap is a range of memory reachable from two slices, A and B are
+// indexes in Report.Spans. The range is [ALo:AHi] of the first slice and
+// [BLo:BHi] of the second.
+type Overlap struct {
+	Kind     Kind
+	A, B     int
+	ALo, AHi int
+	BLo, BHi int
+}
+
+// Report is what Inspect found about a set of slices.
+type Report struct {
+	Spans []Span
+	// Group[i] numbers the backing array of Spans[i], slices with the same
+	// number share it; -1 for a slice that can't reach any memory.
+	Group []int
+	// Retained[i] is the number of bytes Spans[i] keeps alive without
+	// seeing them: the extent of its group, from the lowest address any
+	// slice of the group starts at to the highest one any reaches with its
+	// capacity, minus the bytes of its own elements. That takes in its tail,
+	// the array before it, and the memory past its capacity that other
+	// slices reach. It's a lower bound, the array may be larger than what
+	// the inspected slices cover.
+	Retained []uintptr
+	Overlaps []Overlap
+
+	extents [][2]uintptr // per group
+}
+
+// Inspect groups spans by backing array and finds their overlaps.
+func Inspect(spans ...Span) *Report {
+	r := &Report{
+		Spans:    spans,
+		Group:    make([]int, len(spans)),
+		Retained: make([]uintptr, len(spans)),
+	}
+
+	// sweep the spans in address order, joining those reaching into the
+	// extent of the group so far
+	order := make([]int, 0, len(spans))
+	for i, s := range spans {
+		r.Group[i] = -1
+		if !s.empty() {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return spans[order[a]].Data < spans[order[b]].Data })
+	for _, i := range order {
+		s := spans[i]
+		if g := len(r.extents) - 1; g >= 0 && s.Data < r.extents[g][1] {
+			r.extents[g][1] = max(r.extents[g][1], s.end(s.Cap))
+			r.Group[i] = g
+			continue
+		}
+		r.extents = append(r.extents, [2]uintptr{s.Data, s.end(s.Cap)})
+		r.Group[i] = len(r.extents) - 1
+	}
+	// number the groups in the order of the spans instead of the addresses
+	renumber := make([]int, len(r.extents))
+	extents := r.extents[:0:0]
+	for i := range renumber {
+		renumber[i] = -1
+	}
+	for i, g := range r.Group {
+		if g >= 0 && renumber[g] < 0 {
+			renumber[g] = len(extents)
+			extents = append(extents, r.extents[g])
+		}
+		if g >= 0 {
+			r.Group[i] = renumber[g]
+		}
+	}
+	r.extents = extents
+
+	for i, s := range spans {
+		if g := r.Group[i]; g >= 0 {
+			r.Retained[i] = r.extents[g][1] - r.extents[g][0] - uintptr(s.Len)*s.Size
+		}
+	}
+
+	for i := range spans {
+		for j := i + 1; j < len(spans); j++ {
+			if r.Group[i] < 0 || r.Group[i] != r.Group[j] {
+				continue
+			}
+			a, b := spans[i], spans[j]
+			r.add(Shared, i, j, a.Data, a.end(a.Len), b.Data, b.end(b.Len))
+			r.add(Clobber, i, j, a.end(a.Len), a.end(a.Cap), b.Data, b.end(b.Len))
+			r.add(Clobber, j, i, b.end(b.Len), b.end(b.Cap), a.Data, a.end(a.Len))
+		}
+	}
+	return r
+}
+
+// add records the overlap of [alo, ahi) in Spans[a] and [blo, bhi) in
+// Spans[b], if any.
+func (r *Report) add(k Kind, a, b int, alo, ahi, blo, bhi uintptr) {
+	lo, hi := max(alo, blo), min(ahi, bhi)
+	if lo >= hi {
+		return
+	}
+	sa, sb := r.Spans[a], r.Spans[b]
+	// the element sizes may differ, round to the elements touched
+	r.Overlaps = append(r.Overlaps, Overlap{
+		Kind: k, A: a, B: b,
+		ALo: int((lo - sa.Data) / sa.Size), AHi: int((hi - sa.Data + sa.Size - 1) / sa.Size),
+		BLo: int((lo - sb.Data) / sb.Size), BHi: int((hi - sb.Data + sb.Size - 1) / sb.Size),
+	})
+}
+
+// describe says what o means, with the names of the spans.
+func (r *Report) describe(o Overlap) string {
+	a, b := r.Spans[o.A].Name, r.Spans[o.B].Name
+	if o.Kind == Shared {
+		return fmt.Sprintf("%s[%d:%d] and %s[%d:%d] are the same elements", a, o.ALo, o.AHi, b, o.BLo, o.BHi)
+	}
+	return fmt.Sprintf("appending to %s overwrites %s[%d:%d], reachable as %s[%d:%d]", a, b, o.BLo, o.BHi, a, o.ALo, o.AHi)
+}
+
+// WriteTable writes the spans as a table, with their offset in their
+// backing array as far as the other spans tell, followed by the overlaps.
+func (r *Report) WriteTable(w io.Writer) error {
+	t := table.New("Slice", "Array", "Offset", "Length", "Capacity", "Tail bytes", "Retained bytes")
+	t.SetAlign(table.Right, 1, 2, 3, 4, 5, 6)
+	for i, s := range r.Spans {
+		g := r.Group[i]
+		if g < 0 {
+			t.AddRow(s.Name, "-", "-", s.Len, s.Cap, 0, 0)
+			continue
+		}
+		t.AddRow(s.Name, g, (s.Data-r.extents[g][0])/s.Size, s.Len, s.Cap, s.Tail(), r.Retained[i])
+	}
+	if err := t.Render(w); err != nil {
+		return err
+	}
+	for _, o := range r.Overlaps {
+		if _, err := fmt.Fprintln(w, r.describe(o)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package alias_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"main/slices/alias"
+)
+
+func TestShares(t *testing.T) {
+	buf := make([]int64, 8)
+	bytes := unsafe.Slice((*byte)(unsafe.Pointer(&buf[0])), 64)
+	for _, c := range []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"sub-slice", alias.Shares(buf, buf[3:5]), true},
+		{"through the capacity", alias.Shares(buf[:3], buf[5:]), true},
+		{"full-slice expression", alias.Shares(buf[:3:3], buf[3:]), false},
+		{"distinct arrays", alias.Shares(buf, make([]int64, 8)), false},
+		{"mixed element sizes", alias.Shares(buf[2:3:3], bytes[23:24:24]), true},
+		{"mixed element sizes, adjacent", alias.Shares(buf[2:3:3], bytes[24:]), false},
+		{"nil", alias.Shares(buf, []int64(nil)), false},
+		{"zero capacity", alias.Shares(buf, buf[4:4:4]), false},
+		{"zero-size elements", alias.Shares(make([]struct{}, 4), make([]struct{}, 4)), false},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestDetach(t *testing.T) {
+	if got := alias.Detach([]int(nil)); got != nil {
+		t.Errorf("Detach(nil) = %#v, want nil", got)
+	}
+	buf := make([]int, 8)
+	if got := alias.Detach(buf[4:4]); got == nil || cap(got) != 0 || alias.Shares(got, buf) {
+		t.Errorf("Detach of an empty slice = %#v, cap %d, want a non-nil empty slice", got, cap(got))
+	}
+
+	for i := range buf {
+		buf[i] = i
+	}
+	s := buf[2:5]
+	got := alias.Detach(s)
+	if !reflect.DeepEqual(got, s) || cap(got) != len(got) || alias.Shares(got, buf) {
+		t.Errorf("Detach(%v) = %v, cap %d, want a copy without spare capacity", s, got, cap(got))
+	}
+}
+
+func TestInspect(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		spans    func() []alias.Span
+		group    []int
+		retained []uintptr
+		overlaps []alias.Overlap
+	}{
+		{
+			name: "sub-slice",
+			spans: func() []alias.Span {
+				buf := make([]int64, 8)
+				return []alias.Span{alias.Of("buf", buf), alias.Of("sub", buf[3:5])}
+			},
+			group:    []int{0, 0},
+			retained: []uintptr{0, 48},
+			overlaps: []alias.Overlap{
+				{Kind: alias.Shared, A: 0, B: 1, ALo: 3, AHi: 5, BLo: 0, BHi: 2},
+				{Kind: alias.Clobber, A: 1, B: 0, ALo: 2, AHi: 5, BLo: 5, BHi: 8},
+			},
+		},
+		{
+			name: "full-slice expressions",
+			spans: func() []alias.Span {
+				buf := make([]int64, 8)
+				return []alias.Span{alias.Of("a", buf[:3:3]), alias.Of("b", buf[3:6:6])}
+			},
+			group:    []int{0, 1},
+			retained: []uintptr{0, 0},
+		},
+		{
+			name: "distinct arrays",
+			spans: func() []alias.Span {
+				return []alias.Span{alias.Of("a", make([]int64, 2, 4)), alias.Of("b", make([]int64, 4))}
+			},
+			group:    []int{0, 1},
+			retained: []uintptr{16, 0},
+		},
+		{
+			// c and a don't overlap, b joins them into one group; other
+			// comes first, so it is group 0 wherever its array is
+			name: "grouping sweep",
+			spans: func() []alias.Span {
+				buf := make([]int64, 8)
+				return []alias.Span{
+					alias.Of("other", make([]int64, 1)),
+					alias.Of("c", buf[6:8]),
+					alias.Of("a", buf[0:2:4]),
+					alias.Of("b", buf[3:5:7]),
+				}
+			},
+			group:    []int{0, 1, 1, 1},
+			retained: []uintptr{0, 48, 48, 48},
+			overlaps: []alias.Overlap{
+				{Kind: alias.Clobber, A: 3, B: 1, ALo: 3, AHi: 4, BLo: 0, BHi: 1},
+				{Kind: alias.Clobber, A: 2, B: 3, ALo: 3, AHi: 4, BLo: 0, BHi: 1},
+			},
+		},
+		{
+			// the ranges are rounded out to the elements they touch
+			name: "mixed element sizes",
+			spans: func() []alias.Span {
+				buf := make([]int64, 8)
+				bytes := unsafe.Slice((*byte)(unsafe.Pointer(&buf[0])), 64)
+				return []alias.Span{alias.Of("words", buf[1:3:3]), alias.Of("bytes", bytes[12:20])}
+			},
+			group:    []int{0, 0},
+			retained: []uintptr{40, 48},
+			overlaps: []alias.Overlap{
+				{Kind: alias.Shared, A: 0, B: 1, ALo: 0, AHi: 2, BLo: 0, BHi: 8},
+				{Kind: alias.Clobber, A: 1, B: 0, ALo: 8, AHi: 12, BLo: 1, BHi: 2},
+			},
+		},
+		{
+			name: "no memory",
+			spans: func() []alias.Span {
+				return []alias.Span{alias.Of("nil", []int(nil)), alias.Of("empty", make([]struct{}, 4))}
+			},
+			group:    []int{-1, -1},
+			retained: []uintptr{0, 0},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := alias.Inspect(c.spans()...)
+			if !reflect.DeepEqual(r.Group, c.group) {
+				t.Errorf("groups %v, want %v", r.Group, c.group)
+			}
+			if !reflect.DeepEqual(r.Retained, c.retained) {
+				t.Errorf("retained %v, want %v", r.Retained, c.retained)
+			}
+			if len(r.Overlaps) != 0 || len(c.overlaps) != 0 {
+				if !reflect.DeepEqual(r.Overlaps, c.overlaps) {
+					t.Errorf("overlaps\n%+v\nwant\n%+v", r.Overlaps, c.overlaps)
+				}
+			}
+		})
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteTableError(t *testing.T) {
+	buf := make([]int, 4)
+	r := alias.Inspect(alias.Of("buf", buf), alias.Of("sub", buf[1:2]))
+	if err := r.WriteTable(failWriter{}); err == nil {
+		t.Error("WriteTable to a failing writer returned nil")
+	}
+}
@@ -0,0 +1,86 @@
+package sliceops
+
+// Filter keeps the elements of s for which keep is true, in order, moving
+// them to the front of s, and returns s cut to them. The elements past it
+// are zeroed so they don't keep anything alive; Filter(slices.Clone(s), keep)
+// leaves s alone.
+func Filter[S ~[]E, E any](s S, keep func(E) bool) S {
+	n := 0
+	for _, v := range s {
+		if keep(v) {
+			s[n] = v
+			n++
+		}
+	}
+	clear(s[n:])
+	return s[:n]
+}
+
+// Map returns a new slice of f of each element of s.
+func Map[S ~[]E, E, R any](s S, f func(E) R) []R {
+	out := make([]R, len(s))
+	for i, v := range s {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// MapInPlace replaces each element of s with f of it, when the type stays
+// the same and s isn't needed anymore.
+func MapInPlace[S ~[]E, E any](s S, f func(E) E) {
+	for i, v := range s {
+		s[i] = f(v)
+	}
+}
+
+// Reduce folds s from the left: f(...f(f(init, s[0]), s[1])..., s[n-1]).
+func Reduce[S ~[]E, E, A any](s S, init A, f func(A, E) A) A {
+	acc := init
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// Partition reorders s in place so the elements for which pred is true come
+// first, and returns both parts, which share the array of s. Both keep their
+// order. The elements for which pred is false go through a buffer, the only
+// allocation.
+func Partition[S ~[]E, E any](s S, pred func(E) bool) (yes, no S) {
+	var rest S
+	n := 0
+	for _, v := range s {
+		if pred(v) {
+			s[n] = v
+			n++
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	copy(s[n:], rest)
+	return s[:n:n], s[n:]
+}
+
+// Group is the elements of a slice with the same key, see GroupBy.
+type Group[K comparable, S any] struct {
+	Key   K
+	Items S
+}
+
+// GroupBy groups the elements of s by key, in the order their key first
+// appears, each group keeping the order of s. The groups are new slices.
+func GroupBy[S ~[]E, E any, K comparable](s S, key func(E) K) []Group[K, S] {
+	var groups []Group[K, S]
+	index := make(map[K]int)
+	for _, v := range s {
+		k := key(v)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group[K, S]{Key: k})
+		}
+		groups[i].Items = append(groups[i].Items, v)
+	}
+	return groups
+}
@@ -0,0 +1,60 @@
+package sliceops
+
+import "golang.org/x/exp/slices"
+
+// Dedup removes the repeated elements of s in place, keeping the first of
+// each in order, and returns s cut to them. The elements past it are zeroed.
+// For sorted slices DedupSorted doesn't need the set Dedup builds.
+func Dedup[S ~[]E, E comparable](s S) S {
+	seen := make(map[E]struct{}, len(s))
+	return Filter(s, func(v E) bool {
+		if _, ok := seen[v]; ok {
+			return false
+		}
+		seen[v] = struct{}{}
+		return true
+	})
+}
+
+// DedupSorted removes the runs of equal elements of s in place, like
+// slices.Compact, and zeroes the elements past the result.
+func DedupSorted[S ~[]E, E comparable](s S) S {
+	out := slices.Compact(s)
+	clear(s[len(out):])
+	return out
+}
+
+// The set operations treat a and b as sets: the result has no repeated
+// elements, in the order they first appear in a, then in b. They return new
+// slices, nil only when a is nil, and b too for Union.
+
+// Union returns the elements of a or b.
+func Union[S ~[]E, E comparable](a, b S) S {
+	return Dedup(Concat(a, b))
+}
+
+// Intersection returns the elements of a that are in b.
+func Intersection[S ~[]E, E comparable](a, b S) S {
+	in := set(b)
+	return Dedup(Filter(slices.Clone(a), func(v E) bool {
+		_, ok := in[v]
+		return ok
+	}))
+}
+
+// Difference returns the elements of a that aren't in b.
+func Difference[S ~[]E, E comparable](a, b S) S {
+	in := set(b)
+	return Dedup(Filter(slices.Clone(a), func(v E) bool {
+		_, ok := in[v]
+		return !ok
+	}))
+}
+
+func set[S ~[]E, E comparable](s S) map[E]struct{} {
+	m := make(map[E]struct{}, len(s))
+	for _, v := range s {
+		m[v] = struct{}{}
+	}
+	return m
+}
@@ -0,0 +1,138 @@
+// Package sliceops has the bulk slice operations golang.org/x/exp/slices
+// lacks: joining, splitting, reordering, filtering and set operations. The
+// functions take any slice type S like x/exp/slices, and say whether they
+// work in place or return a new slice.
+package sliceops
+
+import "golang.org/x/exp/slices"
+
+// Concat returns a new slice of the elements of ss in order, allocated
+// once. Without elements it returns an empty slice, nil when every one of
+// ss is nil.
+func Concat[S ~[]E, E any](ss ...S) S {
+	n := 0
+	for _, s := range ss {
+		n += len(s)
+		if n < 0 {
+			panic("sliceops: Concat length overflows int")
+		}
+	}
+	if n == 0 {
+		return none(ss)
+	}
+	out := make(S, 0, n)
+	for _, s := range ss {
+		out = append(out, s...)
+	}
+	return out
+}
+
+// Flatten is Concat of a slice of slices.
+func Flatten[S ~[]E, E any](ss []S) S {
+	return Concat(ss...)
+}
+
+// Chunk splits s in consecutive slices of n elements, the last one may be
+// shorter. The chunks share the array of s but have no spare capacity, so
+// appending to one doesn't overwrite the next. It panics if n < 1.
+func Chunk[S ~[]E, E any](s S, n int) []S {
+	if n < 1 {
+		panic("sliceops: Chunk size must be positive")
+	}
+	chunks := make([]S, 0, (len(s)+n-1)/n)
+	for i := 0; i < len(s); i += n {
+		end := min(i+n, len(s))
+		chunks = append(chunks, s[i:end:end])
+	}
+	return chunks
+}
+
+// Window returns the len(s)-n+1 slices of n consecutive elements of s, none
+// when s is shorter than n. Like chunks, the windows share the array of s
+// without spare capacity. It panics if n < 1.
+func Window[S ~[]E, E any](s S, n int) []S {
+	if n < 1 {
+		panic("sliceops: Window size must be positive")
+	}
+	if len(s) < n {
+		return nil
+	}
+	windows := make([]S, 0, len(s)-n+1)
+	for i := 0; i+n <= len(s); i++ {
+		windows = append(windows, s[i:i+n:i+n])
+	}
+	return windows
+}
+
+// Interleave returns a new slice taking one element of each of ss in turn,
+// skipping the slices that ran out: Interleave({1, 2, 3}, {a}) is {1, a, 2, 3}.
+// Like Concat, it returns nil only when every one of ss is nil.
+func Interleave[S ~[]E, E any](ss ...S) S {
+	n, longest := 0, 0
+	for _, s := range ss {
+		n += len(s)
+		longest = max(longest, len(s))
+	}
+	if n == 0 {
+		return none(ss)
+	}
+	out := make(S, 0, n)
+	for i := 0; i < longest; i++ {
+		for _, s := range ss {
+			if i < len(s) {
+				out = append(out, s[i])
+			}
+		}
+	}
+	return out
+}
+
+// none is the result of joining the empty slices ss.
+func none[S ~[]E, E any](ss []S) S {
+	for _, s := range ss {
+		if s != nil {
+			return S{}
+		}
+	}
+	return nil
+}
+
+// Rotate rotates s in place k positions to the left, or -k to the right
+// when k is negative: Rotate({1, 2, 3, 4}, 1) gives {2, 3, 4, 1}.
+func Rotate[S ~[]E, E any](s S, k int) {
+	if len(s) == 0 {
+		return
+	}
+	k %= len(s)
+	if k < 0 {
+		k += len(s)
+	}
+	slices.Reverse(s[:k])
+	slices.Reverse(s[k:])
+	slices.Reverse(s)
+}
+
+// Pair is an element of each of two slices, see Zip.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs the elements of a and b with the same index, up to the length
+// of the shorter one.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	out := make([]Pair[A, B], min(len(a), len(b)))
+	for i := range out {
+		out[i] = Pair[A, B]{a[i], b[i]}
+	}
+	return out
+}
+
+// Unzip splits pairs into the slice of their first and of their second elements.
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a, b := make([]A, len(pairs)), make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
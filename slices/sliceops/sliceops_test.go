@@ -0,0 +1,135 @@
+package sliceops_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"main/slices/sliceops"
+)
+
+func TestRotate(t *testing.T) {
+	for _, c := range []struct {
+		k    int
+		want []int
+	}{
+		{0, []int{1, 2, 3, 4, 5}},
+		{1, []int{2, 3, 4, 5, 1}},
+		{-1, []int{5, 1, 2, 3, 4}},
+		{5, []int{1, 2, 3, 4, 5}},
+		{7, []int{3, 4, 5, 1, 2}},
+		{-7, []int{4, 5, 1, 2, 3}},
+		{-10, []int{1, 2, 3, 4, 5}},
+	} {
+		s := []int{1, 2, 3, 4, 5}
+		sliceops.Rotate(s, c.k)
+		if !slices.Equal(s, c.want) {
+			t.Errorf("Rotate by %d: got %v, want %v", c.k, s, c.want)
+		}
+	}
+	sliceops.Rotate([]int(nil), 3) // must not divide by zero
+}
+
+func TestChunk(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	for _, c := range []struct {
+		n    int
+		want [][]int
+	}{
+		{1, [][]int{{1}, {2}, {3}, {4}, {5}}},
+		{2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{5, [][]int{{1, 2, 3, 4, 5}}},
+		{6, [][]int{{1, 2, 3, 4, 5}}},
+	} {
+		got := sliceops.Chunk(s, c.n)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Chunk by %d: got %v, want %v", c.n, got, c.want)
+		}
+		for i, chunk := range got {
+			if cap(chunk) != len(chunk) {
+				t.Errorf("Chunk by %d: chunk %d has spare capacity", c.n, i)
+			}
+		}
+	}
+	if got := sliceops.Chunk([]int{}, 2); len(got) != 0 {
+		t.Errorf("Chunk of an empty slice: got %v", got)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	for _, c := range []struct {
+		n    int
+		want [][]int
+	}{
+		{1, [][]int{{1}, {2}, {3}, {4}}},
+		{3, [][]int{{1, 2, 3}, {2, 3, 4}}},
+		{4, [][]int{{1, 2, 3, 4}}},
+		{5, nil},
+	} {
+		got := sliceops.Window(s, c.n)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Window of %d: got %v, want %v", c.n, got, c.want)
+		}
+		for i, w := range got {
+			if cap(w) != len(w) {
+				t.Errorf("Window of %d: window %d has spare capacity", c.n, i)
+			}
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	s := []int{7, 2, 9, 4, 1, 8, 6, 3}
+	even, odd := sliceops.Partition(s, func(v int) bool { return v%2 == 0 })
+	if !slices.Equal(even, []int{2, 4, 8, 6}) || !slices.Equal(odd, []int{7, 9, 1, 3}) {
+		t.Errorf("Partition: got %v and %v, want both in their original order", even, odd)
+	}
+	if !slices.Equal(s, []int{2, 4, 8, 6, 7, 9, 1, 3}) {
+		t.Errorf("Partition left %v", s)
+	}
+	if cap(even) != len(even) {
+		t.Error("appending to the first part would overwrite the second")
+	}
+}
+
+func TestSets(t *testing.T) {
+	a, b := []int{3, 1, 3, 2, 5}, []int{4, 2, 4, 3, 6}
+	for _, c := range []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Union", sliceops.Union(a, b), []int{3, 1, 2, 5, 4, 6}},
+		{"Intersection", sliceops.Intersection(a, b), []int{3, 2}},
+		{"Difference", sliceops.Difference(a, b), []int{1, 5}},
+		{"Dedup", sliceops.Dedup([]int{2, 1, 2, 3, 1}), []int{2, 1, 3}},
+		{"DedupSorted", sliceops.DedupSorted([]int{1, 1, 2, 3, 3, 3}), []int{1, 2, 3}},
+	} {
+		if !slices.Equal(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if !slices.Equal(a, []int{3, 1, 3, 2, 5}) || !slices.Equal(b, []int{4, 2, 4, 3, 6}) {
+		t.Errorf("the set operations modified their inputs: %v, %v", a, b)
+	}
+}
+
+func TestConcatEmpty(t *testing.T) {
+	if got := sliceops.Concat[[]int](); got != nil {
+		t.Errorf("Concat() = %#v, want nil", got)
+	}
+	if got := sliceops.Concat([]int(nil), nil); got != nil {
+		t.Errorf("Concat(nil, nil) = %#v, want nil", got)
+	}
+	if got := sliceops.Concat([]int(nil), []int{}); got == nil || len(got) != 0 {
+		t.Errorf("Concat(nil, {}) = %#v, want an empty slice", got)
+	}
+	if got := sliceops.Union([]int{}, nil); got == nil {
+		t.Error("Union({}, nil) = nil, want an empty slice")
+	}
+	if got := sliceops.Interleave([]int{}, []int{}); got == nil {
+		t.Error("Interleave({}, {}) = nil, want an empty slice")
+	}
+}
@@ -2,9 +2,16 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"math"
+	"os"
 	"reflect"
-	"sort"
-	"strings"
+
+	"main/slices/alias"
+	"main/slices/sliceops"
+	"main/slices/stats"
+	"main/slices/table"
+	"main/slices/vector"
 )
 
 func main() {
@@ -30,17 +37,12 @@ func main() {
 	// Length is the number of elements in the slice, and capacity is the total number
 	// of elements in the underlying array, starting from the first element of the slice.
 	// The capacity of sl3 is 5 because it starts at index 3 of sl2, which has 8 elements.
-	// Create a table header
-	header := "| Slice | Length | Capacity |"
-	divider := "+" + strings.Repeat("-", len(header)-2) + "+"
-
-	// Print the table header and rows
-	fmt.Println(divider)
-	fmt.Println(header)
-	fmt.Println(divider)
-	fmt.Printf("| %-5s | %-6d | %-8d |\n", "sl2", len(sl2), cap(sl2))
-	fmt.Printf("| %-5s | %-6d | %-8d |\n", "sl3", len(sl3), cap(sl3))
-	fmt.Println(divider)
+	t := table.New("Slice", "Length", "Capacity").SetAlign(table.Right, 1, 2)
+	t.AddRow("sl2", len(sl2), cap(sl2))
+	t.AddRow("sl3", len(sl3), cap(sl3))
+	if err := t.Render(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
 	/* fmt.Println("----------------------------")
 	fmt.Println("| Slice | Length | Capacity |")
 	fmt.Println("----------------------------")
@@ -54,127 +56,79 @@ func main() {
 	// It extends sl3 to its maximum capacity based on the underlying array (which is sl2).
 	fmt.Printf("last 5 entries of sl2 (via sl3) %#v\n", sl3[:5])
 
-	var sl4 []int
+	// alias shows what sl3 shares with sl2: appending to sl3 writes over
+	// sl2[5:], unless sl3 is detached first.
+	header := make([]byte, 1<<20)[:16]
+	r := alias.Inspect(alias.Of("sl2", sl2), alias.Of("sl3", sl3), alias.Of("header", header))
+	if err := r.WriteTable(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	header = alias.Detach(header)
+	fmt.Printf("detached: sl3 shares with sl2 %v, a copy %v; header keeps %d bytes alive\n\n",
+		alias.Shares(sl3, sl2), alias.Shares(alias.Detach(sl3), sl2), cap(header))
+
+	// Vector replaces the appendInt, appendString and appendBool helpers,
+	// which were the same function written for three element types.
+	sl4 := vector.New[int](0)
 	for i := 0; i <= 100; i++ {
-		sl4 = appendInt(sl4, i)
+		sl4.Push(i)
 	}
-	fmt.Printf("sl4: %v\n", sl4)
+	fmt.Printf("sl4: %v (len %d, cap %d)\n", sl4.Slice(), sl4.Len(), sl4.Cap())
+	// appendInt printed every reallocation, a Vector counts them instead
+	st := sl4.Stats()
+	fmt.Printf("sl4: %d reallocations, %d bytes copied, at most %d slots unused\n", st.Reallocations, st.BytesCopied, st.PeakWasted)
 
-	var sl5 []string
+	sl5 := vector.New[string](0)
 	for i := 0; i < 5; i++ {
-		sl5 = appendString(sl5, "Hello"+fmt.Sprint(i))
+		sl5.Push("Hello" + fmt.Sprint(i))
 	}
-	fmt.Printf("\nsl5: %v\n\n", sl5)
+	sl5.Insert(0, "Hi")
+	last, _ := sl5.Pop()
+	fmt.Printf("\nsl5: %v, popped %s\n", sl5.Slice(), last)
 
-	var sl6 []bool
+	sl6 := vector.New[bool](0)
 	for i := 0; i < 5; i++ {
-		if i%2 == 0 {
-			sl6 = appendBool(sl6, true)
-		} else {
-			sl6 = appendBool(sl6, false)
-		}
+		sl6.Push(i%2 == 0)
 	}
-	fmt.Printf("\nsl6: %v\n", sl6)
-
-	fmt.Println(concat([]string{"A", "B", "C"}, []string{"D", "E", "F"}))
-
-	fmt.Println(median([]float64{2, 1, 3, 4, 5}))
-	fmt.Printf("TypeOf: %v\n", reflect.TypeOf(2))
-	// fmt.Printf("reflect.ArrayOf(2, reflect.TypeOf(2)): %v\n", reflect.ArrayOf(2, reflect.TypeOf(sl6)))
-
-}
-
-func median(values []float64) (float64, error) {
-	if len(values) == 0 {
-		return 0, fmt.Errorf("can't get median of an empty slice")
+	if _, err := sl6.Get(5); err != nil {
+		fmt.Printf("sl6: %v, %v\n\n", sl6.Slice(), err)
 	}
-	sort.Float64s(values)
-	fmt.Printf("sorted  values: %v\n", values)
-
-	i := len(values) / 2
-	fmt.Printf("i: %v\n", i)
-	fmt.Printf("(len(values) mod 2): %v\n", len(values)%2)
 
-	if len(values)%2 == 1 {
-		return values[i], nil
+	// a ring keeps the last few events
+	events := vector.NewRing[string](3)
+	for _, e := range []string{"start", "load", "parse", "save", "stop"} {
+		events.PushBack(e)
 	}
-	fmt.Printf("values[i-1]: %v\n", values[i-1])
-	fmt.Printf("values[i]: %v\n", values[i])
-
-	v := (values[i-1] + values[i]) / 2
-
-	return v, nil
-}
-
-func concat(s1, s2 []string) []string {
-	s := make([]string, len(s1)+len(s2))
-	copy(s, s1)
-	copy(s[len(s1):], s2)
-
-	return s
-}
-
-// appendInt appends an integer 'v' to the slice 's'.
-func appendInt(s []int, v int) []int {
-	// Get the index we want to append to, initially set to the length of 's'.
-	index := len(s) // 0 indexed
-
-	// Check if there is enough capacity in the slice to append the new element.
-	if len(s) < cap(s) {
-		// If there's room in the slice's capacity, extend the slice by one element.
-		s = s[:len(s)+1]
-	} else {
-		// If there's not enough capacity, reallocate and copy the slice.
-		fmt.Printf("\nreallocate: %d->%d\n", len(s), 2*len(s)+1)
-
-		// Create a new slice 's2' with doubled capacity and one more element.
-		s2 := make([]int, 2*len(s)+1)
-
-		// Copy the elements from the old slice 's' to the new slice 's2'.
-		copy(s2, s)
-
-		// Update the reference of 's' to the new slice 's2' and extend it by one element.
-		s = s2[:len(s)+1]
-	}
-
-	// Set the value 'v' at the computed index in the slice.
-	s[index] = v
-
-	// Return the modified slice.
-	return s
-}
-
-func appendString(s []string, v string) []string {
-	i := len(s)
-
-	if len(s) < cap(s) {
-		s = s[:len(s)+1]
-	} else {
-		s2 := make([]string, 2*len(s)+1)
-		copy(s2, s)
-		s = s2[:len(s)+1]
+	fmt.Printf("last %d events: %v\n\n", events.Len(), events.AppendTo(nil))
+
+	// Concat joins any number of slices with a single allocation
+	letters := sliceops.Concat([]string{"A", "B", "C"}, []string{"D", "E", "F"}, []string{"G"})
+	fmt.Println(letters, sliceops.Chunk(letters, 3), sliceops.Interleave(sl2[:3], sl3))
+	fmt.Println(sliceops.Union(sl2[:4], []int{4, 9, 1, 10}), sliceops.Difference(sl2, []int{2, 4, 6, 8}))
+
+	// stats.Median works on a copy, values keeps its order
+	values := []float64{2, 1, 3, 4, 5}
+	m, err := stats.Median(values)
+	fmt.Printf("median of %v: %v %v\n", values, m, err)
+	p90, _ := stats.Percentile([]int{15, 20, 35, 40, 50}, 90, &stats.Options{Method: stats.NearestRank})
+	fmt.Printf("90th percentile (nearest rank): %v\n", p90)
+	_, err = stats.Median([]float64{1, math.NaN()})
+	fmt.Printf("median with a NaN: %v\n\n", err)
+
+	// the same kind of table as the length and capacity one, for a whole dataset
+	latencies := []float64{12, 15, 11, 14, 13, 95, 12, 16, 14, 13, 12, 18, 15, 14, 250}
+	if s, err := stats.Describe(latencies); err == nil {
+		if err := s.WriteTable(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("outliers: %v\n", s.Outliers)
 	}
-	s[i] = v
-	return s
-}
-
-func appendBool(s []bool, v bool) []bool {
-	i := len(s)
-	fmt.Printf("cap 1 of bool slice in %v iteration: %v\n", i, cap(s))
-
-	if cap(s) > len(s) {
-		s = s[:len(s)+1]
-	} else {
-		s2 := make([]bool, 2*len(s)+1)
-
-		copy(s2, s)
-
-		s = s2[:len(s)+1]
-
-		fmt.Printf("cap 2 of bool slice in %v iteration : %v\n", i, cap(s))
+	if h, err := stats.NewHistogram(latencies, stats.Sturges); err == nil {
+		if err := h.Draw(os.Stdout, 30); err != nil {
+			log.Fatal(err)
+		}
 	}
+	fmt.Printf("TypeOf: %v\n", reflect.TypeOf(2))
+	// fmt.Printf("reflect.ArrayOf(2, reflect.TypeOf(2)): %v\n", reflect.ArrayOf(2, reflect.TypeOf(sl6)))
 
-	s[i] = v
-
-	return s
 }
@@ -0,0 +1,334 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/exp/slices"
+
+	"main/slices/table"
+)
+
+// Moments accumulates the count, mean, variance, skewness, kurtosis and range
+// of a stream in one pass, with Welford's update extended to the third and
+// fourth moments, which stays accurate where the textbook sums of x² cancel.
+// The zero value is ready to use. NaNs are ignored.
+type Moments struct {
+	n          int64
+	mean       float64
+	m2, m3, m4 float64 // sums of the powers of the deviations from the mean
+	min, max   float64
+}
+
+// Add adds an observation.
+func (m *Moments) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	if m.n == 0 || x < m.min {
+		m.min = x
+	}
+	if m.n == 0 || x > m.max {
+		m.max = x
+	}
+
+	n1 := float64(m.n)
+	m.n++
+	n := float64(m.n)
+	delta := x - m.mean
+	dn := delta / n
+	dn2 := dn * dn
+	term := delta * dn * n1
+
+	m.mean += dn
+	m.m4 += term*dn2*(n*n-3*n+3) + 6*dn2*m.m2 - 4*dn*m.m3
+	m.m3 += term*dn*(n-2) - 3*dn*m.m2
+	m.m2 += term
+}
+
+// Merge adds the observations accumulated by o, as if they had been added to m.
+func (m *Moments) Merge(o Moments) {
+	if o.n == 0 {
+		return
+	}
+	if m.n == 0 {
+		*m = o
+		return
+	}
+	na, nb := float64(m.n), float64(o.n)
+	n := na + nb
+	d := o.mean - m.mean
+	d2 := d * d
+
+	m4 := m.m4 + o.m4 + d2*d2*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*d2*(na*na*o.m2+nb*nb*m.m2)/(n*n) + 4*d*(na*o.m3-nb*m.m3)/n
+	m3 := m.m3 + o.m3 + d2*d*na*nb*(na-nb)/(n*n) + 3*d*(na*o.m2-nb*m.m2)/n
+	m.m2 += o.m2 + d2*na*nb/n
+	m.m3, m.m4 = m3, m4
+	m.mean += d * nb / n
+	m.n += o.n
+	m.min = math.Min(m.min, o.min)
+	m.max = math.Max(m.max, o.max)
+}
+
+// N returns the number of observations.
+func (m *Moments) N() int64 { return m.n }
+
+// Mean returns the mean, NaN without observations.
+func (m *Moments) Mean() float64 {
+	if m.n == 0 {
+		return math.NaN()
+	}
+	return m.mean
+}
+
+// Variance returns the sample variance (divided by n-1), NaN under two observations.
+func (m *Moments) Variance() float64 {
+	if m.n < 2 {
+		return math.NaN()
+	}
+	return m.m2 / float64(m.n-1)
+}
+
+// StdDev returns the sample standard deviation.
+func (m *Moments) StdDev() float64 { return math.Sqrt(m.Variance()) }
+
+// Skewness returns the population skewness g1, as NumPy and SciPy do by default:
+// 0 for symmetric data, positive with a long right tail.
+func (m *Moments) Skewness() float64 {
+	if m.n < 2 || m.m2 == 0 {
+		return math.NaN()
+	}
+	n := float64(m.n)
+	return math.Sqrt(n) * m.m3 / math.Pow(m.m2, 1.5)
+}
+
+// Kurtosis returns the population excess kurtosis g2: 0 for a normal
+// distribution, positive for heavier tails.
+func (m *Moments) Kurtosis() float64 {
+	if m.n < 2 || m.m2 == 0 {
+		return math.NaN()
+	}
+	n := float64(m.n)
+	return n*m.m4/(m.m2*m.m2) - 3
+}
+
+// Min returns the smallest observation, NaN without observations.
+func (m *Moments) Min() float64 {
+	if m.n == 0 {
+		return math.NaN()
+	}
+	return m.min
+}
+
+// Max returns the largest observation, NaN without observations.
+func (m *Moments) Max() float64 {
+	if m.n == 0 {
+		return math.NaN()
+	}
+	return m.max
+}
+
+// moments accumulates xs, failing on NaNs like the other slice functions.
+func moments[T Number](xs []T) (*Moments, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	var m Moments
+	for _, x := range xs {
+		if x != x {
+			return nil, ErrNaN
+		}
+		m.Add(float64(x))
+	}
+	return &m, nil
+}
+
+// Mean returns the arithmetic mean of xs.
+func Mean[T Number](xs []T) (float64, error) {
+	m, err := moments(xs)
+	if err != nil {
+		return 0, err
+	}
+	return m.Mean(), nil
+}
+
+// Variance returns the sample variance of xs, which needs two values.
+func Variance[T Number](xs []T) (float64, error) {
+	m, err := moments(xs)
+	if err != nil {
+		return 0, err
+	}
+	if m.N() < 2 {
+		return 0, fmt.Errorf("%w: the variance needs 2 values", ErrEmpty)
+	}
+	return m.Variance(), nil
+}
+
+// StdDev returns the sample standard deviation of xs, which needs two values.
+func StdDev[T Number](xs []T) (float64, error) {
+	v, err := Variance(xs)
+	return math.Sqrt(v), err
+}
+
+// MinMax returns the smallest and the largest element of xs.
+func MinMax[T Number](xs []T) (lo, hi T, err error) {
+	if len(xs) == 0 {
+		return lo, hi, ErrEmpty
+	}
+	lo, hi = xs[0], xs[0]
+	for _, x := range xs {
+		if x != x {
+			return lo, hi, ErrNaN
+		}
+		lo, hi = min(lo, x), max(hi, x)
+	}
+	return lo, hi, nil
+}
+
+// Mode returns the most frequent values of xs in increasing order,
+// several when there is a tie.
+func Mode[T Number](xs []T) ([]T, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	counts := make(map[T]int, len(xs))
+	best := 0
+	for _, x := range xs {
+		if x != x {
+			return nil, ErrNaN
+		}
+		counts[x]++
+		best = max(best, counts[x])
+	}
+	var modes []T
+	for x, c := range counts {
+		if c == best {
+			modes = append(modes, x)
+		}
+	}
+	slices.Sort(modes)
+	return modes, nil
+}
+
+// Skewness returns the population skewness of xs, see Moments.Skewness.
+func Skewness[T Number](xs []T) (float64, error) {
+	m, err := moments(xs)
+	if err != nil {
+		return 0, err
+	}
+	return m.Skewness(), nil
+}
+
+// Kurtosis returns the population excess kurtosis of xs, see Moments.Kurtosis.
+func Kurtosis[T Number](xs []T) (float64, error) {
+	m, err := moments(xs)
+	if err != nil {
+		return 0, err
+	}
+	return m.Kurtosis(), nil
+}
+
+// FiveNumber is Tukey's five-number summary, the numbers of a box plot.
+// Values beyond 1.5 IQR from the quartiles are outliers.
+type FiveNumber struct {
+	Min, Q1, Median, Q3, Max float64
+	LowerFence, UpperFence   float64
+	Outliers                 []float64 // in increasing order
+}
+
+// IQR returns the interquartile range.
+func (f *FiveNumber) IQR() float64 { return f.Q3 - f.Q1 }
+
+// Summarize returns the five-number summary of xs, quartiles computed with R7.
+func Summarize[T Number](xs []T) (*FiveNumber, error) {
+	a, err := clean(xs, NaNError)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(a)
+	q, _ := Quantiles(a, []float64{0.25, 0.5, 0.75}, nil)
+
+	f := &FiveNumber{Min: float64(a[0]), Q1: q[0], Median: q[1], Q3: q[2], Max: float64(a[len(a)-1])}
+	f.LowerFence = f.Q1 - 1.5*f.IQR()
+	f.UpperFence = f.Q3 + 1.5*f.IQR()
+	for _, x := range a {
+		if v := float64(x); v < f.LowerFence || v > f.UpperFence {
+			f.Outliers = append(f.Outliers, v)
+		}
+	}
+	return f, nil
+}
+
+// Summary describes a dataset.
+type Summary struct {
+	N                  int64
+	Mean, StdDev       float64
+	Skewness, Kurtosis float64
+	FiveNumber
+	// Exact is false when the quartiles are t-digest estimates and the outliers aren't listed.
+	Exact bool
+}
+
+// Describe summarizes xs exactly.
+func Describe[T Number](xs []T) (*Summary, error) {
+	m, err := moments(xs)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Summarize(xs)
+	if err != nil {
+		return nil, err
+	}
+	return &Summary{
+		N: m.N(), Mean: m.Mean(), StdDev: m.StdDev(),
+		Skewness: m.Skewness(), Kurtosis: m.Kurtosis(),
+		FiveNumber: *f, Exact: true,
+	}, nil
+}
+
+// DescribeSeq summarizes a stream in one pass and bounded memory: the moments
+// are exact, the quartiles come from a TDigest. seq has the shape of Go
+// 1.23's iter.Seq. NaNs are ignored.
+func DescribeSeq(seq func(yield func(float64) bool)) (*Summary, error) {
+	var m Moments
+	td := NewTDigest(DefaultCompression)
+	seq(func(x float64) bool {
+		m.Add(x)
+		td.Add(x)
+		return true
+	})
+	if m.N() == 0 {
+		return nil, ErrEmpty
+	}
+
+	s := &Summary{
+		N: m.N(), Mean: m.Mean(), StdDev: m.StdDev(),
+		Skewness: m.Skewness(), Kurtosis: m.Kurtosis(),
+	}
+	s.Min, s.Q1, s.Median, s.Q3, s.Max = m.Min(), td.Quantile(0.25), td.Quantile(0.5), td.Quantile(0.75), m.Max()
+	s.LowerFence = s.Q1 - 1.5*s.IQR()
+	s.UpperFence = s.Q3 + 1.5*s.IQR()
+	return s, nil
+}
+
+// WriteTable writes s as a two column table.
+func (s *Summary) WriteTable(w io.Writer) error {
+	t := table.New("Statistic", "Value").SetAlign(table.Right, 1)
+	t.AddRow("n", s.N)
+	for _, r := range []struct {
+		name string
+		v    float64
+	}{
+		{"mean", s.Mean}, {"std dev", s.StdDev},
+		{"min", s.Min}, {"q1", s.Q1}, {"median", s.Median}, {"q3", s.Q3}, {"max", s.Max},
+		{"skewness", s.Skewness}, {"kurtosis", s.Kurtosis},
+	} {
+		t.AddRow(r.name, fmt.Sprintf("%.4g", r.v))
+	}
+	if s.Exact {
+		t.AddRow("outliers", len(s.Outliers))
+	}
+	return t.Render(w)
+}
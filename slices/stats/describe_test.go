@@ -0,0 +1,246 @@
+package stats_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"main/slices/stats"
+)
+
+func near(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// TestMoments checks the moments of 2, 4, 4, 4, 5, 5, 7, 9: deviations
+// -3, -1, -1, -1, 0, 0, 2, 4 from the mean 5, whose squares, cubes and fourth
+// powers sum to 32, 42 and 356.
+func TestMoments(t *testing.T) {
+	var m stats.Moments
+	for _, x := range []float64{2, 4, 4, 4, math.NaN(), 5, 5, 7, 9} {
+		m.Add(x)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"n", float64(m.N()), 8},
+		{"mean", m.Mean(), 5},
+		{"variance", m.Variance(), 32.0 / 7},
+		{"std dev", m.StdDev(), math.Sqrt(32.0 / 7)},
+		{"skewness", m.Skewness(), math.Sqrt(8) * 42 / math.Pow(32, 1.5)}, // 0.65625
+		{"kurtosis", m.Kurtosis(), 8*356.0/(32*32) - 3},                   // -0.21875
+		{"min", m.Min(), 2},
+		{"max", m.Max(), 9},
+	} {
+		if !near(c.got, c.want, 1e-12) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	var empty stats.Moments
+	for name, v := range map[string]float64{
+		"mean": empty.Mean(), "variance": empty.Variance(), "skewness": empty.Skewness(),
+		"kurtosis": empty.Kurtosis(), "min": empty.Min(), "max": empty.Max(),
+	} {
+		if !math.IsNaN(v) {
+			t.Errorf("%s without observations = %v, want NaN", name, v)
+		}
+	}
+}
+
+// TestMomentsCancellation checks the variance of values sharing a large
+// offset, where the sum of the squares loses every digit.
+func TestMomentsCancellation(t *testing.T) {
+	xs := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+	v, err := stats.Variance(xs)
+	if err != nil || !near(v, 30, 1e-9) {
+		t.Errorf("Variance = %v, %v, want 30", v, err)
+	}
+	if s, err := stats.Skewness(xs); err != nil || math.Abs(s) > 1e-6 {
+		t.Errorf("Skewness = %v, %v, want 0", s, err)
+	}
+}
+
+// TestMomentsMerge checks that merging the moments of two parts gives what
+// adding their values one by one does.
+func TestMomentsMerge(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([]float64, 1000)
+	for i := range xs {
+		xs[i] = 100 + r.ExpFloat64()*10
+	}
+
+	var all stats.Moments
+	for _, x := range xs {
+		all.Add(x)
+	}
+	for _, split := range []int{0, 1, 300, 500, 999, 1000} {
+		var a, b stats.Moments
+		for _, x := range xs[:split] {
+			a.Add(x)
+		}
+		for _, x := range xs[split:] {
+			b.Add(x)
+		}
+		a.Merge(b)
+
+		if a.N() != all.N() || a.Min() != all.Min() || a.Max() != all.Max() {
+			t.Errorf("split %d: n, min, max = %d, %v, %v, want %d, %v, %v",
+				split, a.N(), a.Min(), a.Max(), all.N(), all.Min(), all.Max())
+		}
+		for _, c := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"mean", a.Mean(), all.Mean()},
+			{"variance", a.Variance(), all.Variance()},
+			{"skewness", a.Skewness(), all.Skewness()},
+			{"kurtosis", a.Kurtosis(), all.Kurtosis()},
+		} {
+			if !near(c.got, c.want, 1e-9) {
+				t.Errorf("split %d: merged %s = %v, want %v", split, c.name, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestDescribeErrors(t *testing.T) {
+	if _, err := stats.Mean([]float64{}); !errors.Is(err, stats.ErrEmpty) {
+		t.Errorf("Mean of nothing: err = %v, want ErrEmpty", err)
+	}
+	if _, err := stats.Variance([]int{1}); !errors.Is(err, stats.ErrEmpty) {
+		t.Errorf("Variance of one value: err = %v, want ErrEmpty", err)
+	}
+	if _, err := stats.Describe([]float64{1, math.NaN()}); !errors.Is(err, stats.ErrNaN) {
+		t.Errorf("Describe with a NaN: err = %v, want ErrNaN", err)
+	}
+	if _, _, err := stats.MinMax([]float64{1, math.NaN()}); !errors.Is(err, stats.ErrNaN) {
+		t.Errorf("MinMax with a NaN: err = %v, want ErrNaN", err)
+	}
+	if _, err := stats.DescribeSeq(func(yield func(float64) bool) { yield(math.NaN()) }); !errors.Is(err, stats.ErrEmpty) {
+		t.Errorf("DescribeSeq of a NaN: err = %v, want ErrEmpty", err)
+	}
+}
+
+func TestMode(t *testing.T) {
+	for _, c := range []struct {
+		xs, want []int
+	}{
+		{[]int{3}, []int{3}},
+		{[]int{1, 2, 2, 3}, []int{2}},
+		{[]int{3, 3, 1, 2, 2, 1, 5}, []int{1, 2, 3}},
+	} {
+		got, err := stats.Mode(c.xs)
+		if err != nil || !slices.Equal(got, c.want) {
+			t.Errorf("Mode(%v) = %v, %v, want %v", c.xs, got, err, c.want)
+		}
+	}
+	if _, err := stats.Mode([]float64{1, math.NaN()}); !errors.Is(err, stats.ErrNaN) {
+		t.Errorf("Mode with a NaN: err = %v, want ErrNaN", err)
+	}
+}
+
+// TestSummarize checks the fences of 1..9 and 100: the R-7 quartiles are
+// 3.25 and 7.75, the IQR 4.5, so the fences are at -3.5 and 14.5.
+func TestSummarize(t *testing.T) {
+	f, err := stats.Summarize([]int{9, 1, 2, 100, 3, 4, 5, 6, 7, 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := stats.FiveNumber{
+		Min: 1, Q1: 3.25, Median: 5.5, Q3: 7.75, Max: 100,
+		LowerFence: -3.5, UpperFence: 14.5, Outliers: []float64{100},
+	}
+	if f.Min != want.Min || f.Q1 != want.Q1 || f.Median != want.Median || f.Q3 != want.Q3 || f.Max != want.Max ||
+		f.LowerFence != want.LowerFence || f.UpperFence != want.UpperFence || !slices.Equal(f.Outliers, want.Outliers) {
+		t.Errorf("Summarize = %+v, want %+v", *f, want)
+	}
+
+	// on the fence isn't an outlier
+	f, _ = stats.Summarize([]float64{-3.5, 3.25, 3.25, 7.75, 7.75, 14.5})
+	if len(f.Outliers) != 0 {
+		t.Errorf("outliers = %v, want none", f.Outliers)
+	}
+}
+
+// TestDescribeSeq compares the streaming summary with the exact one.
+func TestDescribeSeq(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	xs := make([]float64, 10_000)
+	for i := range xs {
+		xs[i] = r.NormFloat64()
+	}
+	exact, err := stats.Describe(xs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := stats.DescribeSeq(func(yield func(float64) bool) {
+		for _, x := range xs {
+			if !yield(x) {
+				return
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.N != exact.N || s.Exact || s.Min != exact.Min || s.Max != exact.Max {
+		t.Errorf("n, exact, min, max = %d, %v, %v, %v, want %d, false, %v, %v",
+			s.N, s.Exact, s.Min, s.Max, exact.N, exact.Min, exact.Max)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+		tol       float64
+	}{
+		{"mean", s.Mean, exact.Mean, 1e-9},
+		{"std dev", s.StdDev, exact.StdDev, 1e-9},
+		{"skewness", s.Skewness, exact.Skewness, 1e-9},
+		{"kurtosis", s.Kurtosis, exact.Kurtosis, 1e-9},
+		{"q1", s.Q1, exact.Q1, 0.01},
+		{"median", s.Median, exact.Median, 0.01},
+		{"q3", s.Q3, exact.Q3, 0.01},
+		{"lower fence", s.LowerFence, exact.LowerFence, 0.05},
+		{"upper fence", s.UpperFence, exact.UpperFence, 0.05},
+	} {
+		if !near(c.got, c.want, c.tol) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	s, err := stats.Describe([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := s.WriteTable(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `+-----------+-------+
+| Statistic | Value |
++-----------+-------+
+| n         |    10 |
+| mean      |  14.5 |
+| std dev   | 30.15 |
+| min       |     1 |
+| q1        |  3.25 |
+| median    |   5.5 |
+| q3        |  7.75 |
+| max       |   100 |
+| skewness  |  2.63 |
+| kurtosis  | 4.998 |
+| outliers  |     1 |
++-----------+-------+
+`
+	if b.String() != want {
+		t.Errorf("WriteTable:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
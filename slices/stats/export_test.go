@@ -0,0 +1,4 @@
+package stats
+
+// MedianOfMedians exposes the worst case pivot to the tests.
+var MedianOfMedians = medianOfMedians[float64]
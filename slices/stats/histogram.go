@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// BinRule chooses the number of bins of a histogram of n values spanning
+// [min, max] with the interquartile range iqr.
+type BinRule func(n int, min, max, iqr float64) int
+
+// FixedBins always uses k bins of equal width.
+func FixedBins(k int) BinRule {
+	return func(int, float64, float64, float64) int { return k }
+}
+
+// Sturges uses log2(n)+1 bins, fine for roughly normal data of moderate size
+// but too few for large or skewed datasets.
+func Sturges(n int, _, _, _ float64) int {
+	return int(math.Ceil(math.Log2(float64(n)))) + 1
+}
+
+// FreedmanDiaconis uses bins of width 2·IQR/n^(1/3), which resists outliers.
+// It falls back to Sturges when the IQR is 0.
+func FreedmanDiaconis(n int, min, max, iqr float64) int {
+	if iqr == 0 {
+		return Sturges(n, min, max, iqr)
+	}
+	width := 2 * iqr / math.Cbrt(float64(n))
+	return clampBins((max - min) / width)
+}
+
+// clampBins converts k bins to an int in [1, maxBins]: a tiny IQR makes k
+// huge or infinite, and int of a float out of range is undefined. NaN gives 1.
+func clampBins(k float64) int {
+	switch {
+	case math.IsNaN(k) || k < 1:
+		return 1
+	case k > maxBins:
+		return maxBins
+	}
+	return int(math.Ceil(k))
+}
+
+// Histogram counts values in bins of equal width: bin i is
+// [Edges[i], Edges[i+1]), the last one includes its upper edge.
+type Histogram struct {
+	Edges  []float64
+	Counts []int
+}
+
+// maxBins bounds what a rule may ask for, Freedman–Diaconis explodes on a tiny IQR.
+const maxBins = 10_000
+
+// NewHistogram bins xs with the number of bins chosen by rule.
+func NewHistogram[T Number](xs []T, rule BinRule) (*Histogram, error) {
+	a, err := clean(xs, NaNError)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(a)
+	lo, hi := float64(a[0]), float64(a[len(a)-1])
+	q, _ := Quantiles(a, []float64{0.25, 0.75}, nil)
+
+	k := min(max(rule(len(a), lo, hi, q[1]-q[0]), 1), maxBins)
+	if lo == hi { // a single value, in a single bin with some width
+		lo, hi, k = lo-0.5, hi+0.5, 1
+	}
+	if math.IsInf(hi-lo, 0) {
+		return nil, fmt.Errorf("%w: infinite range", ErrRange)
+	}
+
+	width := (hi - lo) / float64(k)
+	if width == 0 || math.IsInf(width, 0) {
+		// a range of a few subnormals splits in bins of no width, keep one
+		k, width = 1, hi-lo
+	}
+
+	h := &Histogram{Edges: make([]float64, k+1), Counts: make([]int, k)}
+	for i := range h.Edges {
+		h.Edges[i] = lo + float64(i)*width
+	}
+	h.Edges[k] = hi
+	for _, x := range a {
+		h.Counts[binIndex((float64(x)-lo)/width, k)]++
+	}
+	return h, nil
+}
+
+// binIndex converts the position f, in bin widths from the lower edge, to a
+// bin in [0, k-1], clamping before the conversion since int of a float out
+// of range is undefined.
+func binIndex(f float64, k int) int {
+	switch {
+	case !(f < float64(k-1)): // NaN included
+		return k - 1
+	case f < 0:
+		return 0
+	}
+	return int(f)
+}
+
+// Draw writes h with one bar per bin, the fullest one width characters long.
+func (h *Histogram) Draw(w io.Writer, width int) error {
+	most := slices.Max(h.Counts)
+	labels := make([]string, len(h.Counts))
+	lw := 0
+	for i := range h.Counts {
+		closing := ")"
+		if i == len(h.Counts)-1 {
+			closing = "]"
+		}
+		labels[i] = fmt.Sprintf("[%.4g, %.4g%s", h.Edges[i], h.Edges[i+1], closing)
+		lw = max(lw, len(labels[i]))
+	}
+
+	var b strings.Builder
+	for i, c := range h.Counts {
+		bar := 0
+		if most > 0 {
+			bar = int(math.Round(float64(c) / float64(most) * float64(width)))
+		}
+		fmt.Fprintf(&b, "%-*s %s %d\n", lw, labels[i], strings.Repeat("#", bar), c)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,70 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"main/slices/stats"
+)
+
+// TestFreedmanDiaconisTinyIQR checks that a near-zero IQR, which asks for
+// more bins than an int holds, gives the most bins and not a single one.
+func TestFreedmanDiaconisTinyIQR(t *testing.T) {
+	const most = 10_000
+	for _, c := range []struct {
+		name          string
+		min, max, iqr float64
+		want          int
+	}{
+		{"tiny iqr", 0, 1e6, 1e-300, most},
+		{"infinite range", 0, math.Inf(1), 1, most},
+		{"nan range", 0, math.NaN(), 1, 1},
+		{"normal", 0, 100, 10, 50}, // bins of 2·10/∛1000 = 2
+	} {
+		if got := stats.FreedmanDiaconis(1000, c.min, c.max, c.iqr); got != c.want {
+			t.Errorf("%s: %d bins, want %d", c.name, got, c.want)
+		}
+	}
+
+	xs := make([]float64, 1000)
+	for i := range xs {
+		xs[i] = float64(i) * 1e-300
+	}
+	xs[0] = 1e6
+	h, err := stats.NewHistogram(xs, stats.FreedmanDiaconis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Counts) != most {
+		t.Errorf("%d bins, want %d", len(h.Counts), most)
+	}
+	total := 0
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total != len(xs) || h.Counts[len(h.Counts)-1] != 1 {
+		t.Errorf("%d values binned, %d in the last bin, want %d and 1", total, h.Counts[len(h.Counts)-1], len(xs))
+	}
+}
+
+func TestHistogramSingleValue(t *testing.T) {
+	h, err := stats.NewHistogram([]int{3, 3, 3}, stats.Sturges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Counts) != 1 || h.Counts[0] != 3 || h.Edges[0] != 2.5 || h.Edges[1] != 3.5 {
+		t.Errorf("got edges %v counts %v, want [2.5 3.5] [3]", h.Edges, h.Counts)
+	}
+}
+
+// TestHistogramSubnormalRange checks a range so narrow that the bins would
+// have no width, which made the bin index int(+Inf).
+func TestHistogramSubnormalRange(t *testing.T) {
+	h, err := stats.NewHistogram([]float64{0, 5e-324}, stats.FixedBins(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Counts) != 1 || h.Counts[0] != 2 || h.Edges[0] != 0 || h.Edges[1] != 5e-324 {
+		t.Errorf("got edges %v counts %v, want [0 5e-324] [2]", h.Edges, h.Counts)
+	}
+}
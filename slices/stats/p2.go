@@ -0,0 +1,246 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// P2 estimates one quantile of a stream in constant memory with the P²
+// algorithm (Jain and Chlamtac, 1985): five markers track the minimum, the
+// quantile, the maximum and the quantiles halfway between, and their heights
+// are adjusted with a piecewise parabolic fit as observations arrive.
+//
+// A P2 is not safe for concurrent use.
+type P2 struct {
+	p     float64
+	count int64
+	q     [5]float64 // marker heights, the first observations until there are 5
+	n     [5]int64   // marker positions, 0-based
+	np    [5]float64 // desired marker positions
+	dn    [5]float64 // desired position increments
+}
+
+// NewP2 returns an estimator of the p-quantile, p in [0, 1].
+func NewP2(p float64) (*P2, error) {
+	if !(p >= 0 && p <= 1) {
+		return nil, fmt.Errorf("%w: quantile %v", ErrRange, p)
+	}
+	e := &P2{p: p}
+	e.dn = [5]float64{0, p / 2, p, (1 + p) / 2, 1}
+	return e, nil
+}
+
+// P returns the quantile e estimates.
+func (e *P2) P() float64 { return e.p }
+
+// Count returns the number of observations added, NaNs excluded.
+func (e *P2) Count() int64 { return e.count }
+
+// Add adds an observation. NaNs are ignored.
+func (e *P2) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	if e.count < 5 {
+		e.q[e.count] = x
+		e.count++
+		if e.count == 5 {
+			sort.Float64s(e.q[:])
+			for i := range e.n {
+				e.n[i] = int64(i)
+				e.np[i] = 4 * e.dn[i]
+			}
+		}
+		return
+	}
+	e.count++
+
+	// find the cell of x, stretching the extreme markers if needed
+	var k int
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+		k = 0
+	case x >= e.q[4]:
+		e.q[4] = max(e.q[4], x)
+		k = 3
+	default:
+		for k = 0; x >= e.q[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := range e.np {
+		e.np[i] += e.dn[i]
+	}
+
+	// move the middle markers that are off their desired position by one or more
+	for i := 1; i <= 3; i++ {
+		d := e.np[i] - float64(e.n[i])
+		if (d >= 1 && e.n[i+1]-e.n[i] > 1) || (d <= -1 && e.n[i-1]-e.n[i] < -1) {
+			s := int64(1)
+			if d < 0 {
+				s = -1
+			}
+			q := e.parabolic(i, float64(s))
+			if !(e.q[i-1] < q && q < e.q[i+1]) {
+				q = e.linear(i, s)
+			}
+			e.q[i] = q
+			e.n[i] += s
+		}
+	}
+}
+
+func (e *P2) parabolic(i int, d float64) float64 {
+	n0, n1, n2 := float64(e.n[i-1]), float64(e.n[i]), float64(e.n[i+1])
+	return e.q[i] + d/(n2-n0)*((n1-n0+d)*(e.q[i+1]-e.q[i])/(n2-n1)+(n2-n1-d)*(e.q[i]-e.q[i-1])/(n1-n0))
+}
+
+func (e *P2) linear(i int, d int64) float64 {
+	j := i + int(d)
+	return e.q[i] + float64(d)*(e.q[j]-e.q[i])/float64(e.n[j]-e.n[i])
+}
+
+// Quantile returns the estimate, exact while fewer than 5 observations were
+// added, and NaN before the first one.
+func (e *P2) Quantile() float64 {
+	if e.count == 0 {
+		return math.NaN()
+	}
+	if e.count < 5 {
+		q, _ := Quantile(e.q[:e.count], e.p, nil)
+		return q
+	}
+	return e.q[2]
+}
+
+// Merge adds the observations summarized by o to e. P² has no exact merge:
+// the heights of the markers of both are averaged, weighted by the counts,
+// and put at the positions they should have in the merged stream, which is
+// accurate for samples of the same distribution only.
+// Use a TDigest when merging matters.
+func (e *P2) Merge(o *P2) error {
+	if o.p != e.p {
+		return fmt.Errorf("stats: can't merge the P² estimators of quantiles %v and %v", e.p, o.p)
+	}
+	switch {
+	case o.count == 0:
+		return nil
+	case o.count < 5:
+		for _, x := range o.q[:o.count] {
+			e.Add(x)
+		}
+		return nil
+	case e.count < 5:
+		first := append([]float64{}, e.q[:e.count]...)
+		*e = *o
+		for _, x := range first {
+			e.Add(x)
+		}
+		return nil
+	}
+
+	total := e.count + o.count
+	we, wo := float64(e.count)/float64(total), float64(o.count)/float64(total)
+	e.q[0] = min(e.q[0], o.q[0])
+	e.q[4] = max(e.q[4], o.q[4])
+	for i := 1; i <= 3; i++ {
+		e.q[i] = we*e.q[i] + wo*o.q[i]
+	}
+	e.count = total
+	for i := range e.n {
+		e.np[i] = float64(total-1) * e.dn[i]
+		e.n[i] = int64(math.Round(e.np[i]))
+	}
+	// keep the positions strictly increasing, the parabolic fit divides by their gaps
+	for i := 1; i < 5; i++ {
+		e.n[i] = max(e.n[i], e.n[i-1]+1)
+	}
+	for i := 3; i >= 0; i-- {
+		e.n[i] = min(e.n[i], e.n[i+1]-1)
+	}
+	return nil
+}
+
+const p2Version = 1
+
+// MarshalBinary encodes e, little endian: a version byte, p, the count,
+// then the heights, positions and desired positions of the markers.
+func (e *P2) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte(p2Version)
+	binary.Write(&b, binary.LittleEndian, e.p)
+	binary.Write(&b, binary.LittleEndian, e.count)
+	binary.Write(&b, binary.LittleEndian, e.q)
+	binary.Write(&b, binary.LittleEndian, e.n)
+	binary.Write(&b, binary.LittleEndian, e.np)
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes what MarshalBinary encoded into e.
+func (e *P2) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if v, err := r.ReadByte(); err != nil || v != p2Version {
+		return errors.New("stats: P² encoding: unknown version")
+	}
+	var p float64
+	if err := binary.Read(r, binary.LittleEndian, &p); err != nil {
+		return fmt.Errorf("stats: P² encoding: %w", err)
+	}
+	d, err := NewP2(p)
+	if err != nil {
+		return err
+	}
+	for _, v := range []any{&d.count, &d.q, &d.n, &d.np} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return fmt.Errorf("stats: P² encoding: %w", err)
+		}
+	}
+	if r.Len() != 0 {
+		return errors.New("stats: P² encoding: trailing data")
+	}
+	if err := d.check(); err != nil {
+		return fmt.Errorf("stats: P² encoding: %w", err)
+	}
+	*e = *d
+	return nil
+}
+
+// check reports the first inconsistency of a decoded estimator, which would
+// otherwise panic or give NaNs later.
+func (e *P2) check() error {
+	if e.count < 0 {
+		return fmt.Errorf("negative count %d", e.count)
+	}
+	for _, q := range e.q[:min(e.count, 5)] {
+		if math.IsNaN(q) {
+			return errors.New("NaN marker height")
+		}
+	}
+	if e.count < 5 {
+		return nil
+	}
+	if e.n[0] != 0 || e.n[4] != e.count-1 {
+		return fmt.Errorf("markers at %d and %d, not at the ends of %d observations", e.n[0], e.n[4], e.count)
+	}
+	for i := 1; i < 5; i++ {
+		if e.n[i] <= e.n[i-1] {
+			return fmt.Errorf("marker positions %v not increasing", e.n)
+		}
+		if e.q[i] < e.q[i-1] {
+			return fmt.Errorf("marker heights %v decreasing", e.q)
+		}
+	}
+	for _, np := range e.np {
+		if math.IsNaN(np) || math.IsInf(np, 0) {
+			return fmt.Errorf("desired marker position %v", np)
+		}
+	}
+	return nil
+}
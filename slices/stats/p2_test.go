@@ -0,0 +1,189 @@
+package stats_test
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"main/slices/stats"
+)
+
+// datasets are the streams the estimators are checked on, n values each.
+var datasets = []struct {
+	name string
+	gen  func(r *rand.Rand, i int) float64
+}{
+	{"uniform", func(r *rand.Rand, _ int) float64 { return r.Float64() * 100 }},
+	{"normal", func(r *rand.Rand, _ int) float64 { return r.NormFloat64()*15 + 100 }},
+	// latencies: mostly fast, a long tail
+	{"lognormal", func(r *rand.Rand, _ int) float64 { return math.Exp(r.NormFloat64()*0.8 + 3) }},
+	{"bimodal", func(r *rand.Rand, _ int) float64 {
+		if r.Intn(10) < 7 {
+			return r.NormFloat64()*2 + 10
+		}
+		return r.NormFloat64()*10 + 200
+	}},
+	{"sorted", func(_ *rand.Rand, i int) float64 { return float64(i) }}, // the worst order for P²
+}
+
+// dataset returns the values of datasets[d] and a sorted copy.
+func dataset(d, n int) (data, sorted []float64) {
+	r := rand.New(rand.NewSource(int64(d) + 1))
+	data = make([]float64, n)
+	for i := range data {
+		data[i] = datasets[d].gen(r, i)
+	}
+	sorted = append([]float64{}, data...)
+	sort.Float64s(sorted)
+	return data, sorted
+}
+
+// rankError returns how far the rank of est in sorted is from q, as a fraction of the data.
+// Values equal to est count as half below it.
+func rankError(sorted []float64, q, est float64) float64 {
+	lo := sort.SearchFloat64s(sorted, est)
+	hi := sort.Search(len(sorted), func(i int) bool { return sorted[i] > est })
+	// between lo and hi every rank is exact
+	if float64(lo)/float64(len(sorted)) <= q && q <= float64(hi)/float64(len(sorted)) {
+		return 0
+	}
+	rank := (float64(lo) + float64(hi)) / 2 / float64(len(sorted))
+	return math.Abs(rank - q)
+}
+
+// TestP2RankError bounds the rank errors of P² at 2%: its parabolas fit the
+// median of the bimodal dataset, on the flank of a mode, to within 1.7% only.
+func TestP2RankError(t *testing.T) {
+	const maxErr = 0.02
+	for d, ds := range datasets {
+		data, sorted := dataset(d, 50_000)
+		for _, p := range []float64{0.5, 0.9, 0.99} {
+			e, err := stats.NewP2(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, x := range data {
+				e.Add(x)
+			}
+			if re := rankError(sorted, p, e.Quantile()); re > maxErr {
+				t.Errorf("%s p%g: estimate %g has a rank error of %.3f%%", ds.name, p*100, e.Quantile(), re*100)
+			}
+		}
+	}
+}
+
+func TestP2Merge(t *testing.T) {
+	const shards, maxErr = 8, 0.02
+	for d, ds := range datasets {
+		data, sorted := dataset(d, 50_000)
+		for _, p := range []float64{0.5, 0.9} {
+			merged, _ := stats.NewP2(p)
+			for s := 0; s < shards; s++ {
+				part, _ := stats.NewP2(p)
+				for i := s; i < len(data); i += shards {
+					part.Add(data[i])
+				}
+				if err := merged.Merge(part); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if merged.Count() != int64(len(data)) {
+				t.Fatalf("%s: merged count %d, want %d", ds.name, merged.Count(), len(data))
+			}
+			if re := rankError(sorted, p, merged.Quantile()); re > maxErr {
+				t.Errorf("%s p%g: merged estimate %g has a rank error of %.3f%%", ds.name, p*100, merged.Quantile(), re*100)
+			}
+		}
+	}
+}
+
+// TestP2MergeSmall checks that merging estimators of fewer than 5
+// observations keeps the result exact.
+func TestP2MergeSmall(t *testing.T) {
+	a, _ := stats.NewP2(0.5)
+	b, _ := stats.NewP2(0.5)
+	for _, x := range []float64{5, 1} {
+		a.Add(x)
+	}
+	for _, x := range []float64{3, 4} {
+		b.Add(x)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Quantile(); got != 3.5 {
+		t.Errorf("median of 1 3 4 5 = %v, want 3.5", got)
+	}
+
+	c, _ := stats.NewP2(0.9)
+	if err := a.Merge(c); err == nil {
+		t.Error("merging the estimators of different quantiles succeeded")
+	}
+}
+
+func TestP2Binary(t *testing.T) {
+	data, _ := dataset(1, 1000)
+	e, _ := stats.NewP2(0.75)
+	for _, x := range data {
+		e.Add(x)
+	}
+	b, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d stats.P2
+	if err := d.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if d.Quantile() != e.Quantile() || d.Count() != e.Count() || d.P() != e.P() {
+		t.Errorf("decoded p%v %v of %d, want p%v %v of %d", d.P(), d.Quantile(), d.Count(), e.P(), e.Quantile(), e.Count())
+	}
+}
+
+// patch returns a copy of b with the 8 bytes at off replaced by v, little endian.
+func patch(b []byte, off int, v uint64) []byte {
+	b = append([]byte(nil), b...)
+	binary.LittleEndian.PutUint64(b[off:], v)
+	return b
+}
+
+// TestP2BinaryCorrupt checks that an encoding a P2 can't be in is refused.
+// The layout is a version byte, p at 1, the count at 9, the heights at 17,
+// the positions at 57 and the desired positions at 97.
+func TestP2BinaryCorrupt(t *testing.T) {
+	e, _ := stats.NewP2(0.5)
+	for _, x := range []float64{5, 1, 4, 2, 3, 6, 7} {
+		e.Add(x)
+	}
+	b, _ := e.MarshalBinary()
+	small, _ := stats.NewP2(0.5)
+	small.Add(1)
+	bs, _ := small.MarshalBinary()
+
+	neg := -3
+	for _, c := range []struct {
+		name string
+		b    []byte
+	}{
+		{"negative count", patch(bs, 9, uint64(neg))},
+		{"NaN height before 5", patch(bs, 17, math.Float64bits(math.NaN()))},
+		{"NaN height", patch(b, 17+2*8, math.Float64bits(math.NaN()))},
+		{"decreasing heights", patch(b, 17+3*8, math.Float64bits(-1))},
+		{"repeated position", patch(b, 57+2*8, 1)},
+		{"last position", patch(b, 57+4*8, 100)},
+		{"NaN desired position", patch(b, 97+8, math.Float64bits(math.NaN()))},
+		{"truncated", b[:len(b)-1]},
+		{"bad p", patch(b, 1, math.Float64bits(2))},
+	} {
+		var d stats.P2
+		if err := d.UnmarshalBinary(c.b); err == nil {
+			t.Errorf("%s: decoded %v of %d", c.name, d.Quantile(), d.Count())
+		}
+	}
+	var d stats.P2
+	if err := d.UnmarshalBinary(bs); err != nil || d.Quantile() != 1 {
+		t.Errorf("decoding 1 observation: %v, %v", d.Quantile(), err)
+	}
+}
@@ -0,0 +1,302 @@
+// Package stats computes order statistics and quantiles without reordering
+// the caller's data. It replaces the median of slices/slices.go, which sorted
+// its argument in place.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// Number is the element type of the slices the functions accept.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+var (
+	ErrEmpty = errors.New("stats: empty input")
+	// ErrNaN is returned for input holding a NaN under the NaNError policy.
+	ErrNaN = errors.New("stats: NaN in input")
+	// ErrRange is returned for a quantile outside [0, 1] or a rank outside the input.
+	ErrRange = errors.New("stats: out of range")
+)
+
+// NaNPolicy says what to do with NaNs, which have no place in an order.
+type NaNPolicy int
+
+const (
+	// NaNError fails with ErrNaN, it is the default.
+	NaNError NaNPolicy = iota
+	// NaNSkip ignores the NaNs, as if they weren't in the input.
+	NaNSkip
+	// NaNPropagate returns NaN.
+	NaNPropagate
+)
+
+// Method is how a quantile falling between two data points is computed, named
+// after Hyndman and Fan's "Sample Quantiles in Statistical Packages" (1996).
+// For the sorted data x[0..n-1] and the quantile p:
+type Method int
+
+const (
+	// R7 interpolates linearly at rank (n-1)p. It is the default of R,
+	// NumPy and Excel's PERCENTILE.INC, and the median is the usual one.
+	R7 Method = iota
+	// Hazen interpolates linearly at rank np-1/2, taking the data points as the
+	// midpoints of n equal steps of the distribution (R-5, Hyndman and Fan's type 5).
+	Hazen
+	// NearestRank returns x[ceil(np)-1], a data point, never an interpolation (R-1).
+	NearestRank
+)
+
+func (m Method) String() string {
+	switch m {
+	case R7:
+		return "R-7"
+	case Hazen:
+		return "R-5"
+	case NearestRank:
+		return "nearest-rank"
+	}
+	return fmt.Sprintf("Method(%d)", int(m))
+}
+
+// Options tune Quantile, nil means R7 and NaNError.
+type Options struct {
+	Method Method
+	NaN    NaNPolicy
+}
+
+// Median returns the median of xs, the mean of the two middle values for an
+// even length. A NaN in xs is an error. xs is not modified.
+func Median[T Number](xs []T) (float64, error) {
+	return Quantile(xs, 0.5, nil)
+}
+
+// Percentile is Quantile with p in [0, 100].
+func Percentile[T Number](xs []T, p float64, opts *Options) (float64, error) {
+	return Quantile(xs, p/100, opts)
+}
+
+// Quantile returns the p-quantile of xs, p in [0, 1], in expected O(n) time
+// and O(n) space: xs is copied, not modified.
+func Quantile[T Number](xs []T, p float64, opts *Options) (float64, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if _, err := rank(1, p, opts.Method); err != nil {
+		return 0, err
+	}
+	a, err := clean(xs, opts.NaN)
+	if err != nil {
+		return 0, err
+	}
+	if a == nil {
+		return math.NaN(), nil
+	}
+	h, err := rank(len(a), p, opts.Method)
+	if err != nil {
+		return 0, err
+	}
+
+	lo := int(h)
+	selectK(a, lo)
+	if frac := h - float64(lo); frac > 0 {
+		// after selectK everything past lo is >= a[lo], the next rank is their minimum
+		return lerp(a[lo], slices.Min(a[lo+1:]), frac), nil
+	}
+	return float64(a[lo]), nil
+}
+
+// rank returns the 0-based, maybe fractional, rank of the p-quantile of n values.
+func rank(n int, p float64, m Method) (float64, error) {
+	if !(p >= 0 && p <= 1) {
+		return 0, fmt.Errorf("%w: quantile %v", ErrRange, p)
+	}
+	fn := float64(n)
+	var h float64
+	switch m {
+	case R7:
+		h = (fn - 1) * p
+	case Hazen:
+		h = fn*p - 0.5
+	case NearestRank:
+		h = math.Ceil(fn*p) - 1
+	default:
+		return 0, fmt.Errorf("stats: unknown method %v", m)
+	}
+	return math.Min(math.Max(h, 0), fn-1), nil
+}
+
+func lerp[T Number](a, b T, frac float64) float64 {
+	return float64(a) + frac*(float64(b)-float64(a))
+}
+
+// Quantiles returns the quantiles ps of xs, sorting a copy of xs once,
+// which is faster than repeated Quantile calls for more than a few ps.
+func Quantiles[T Number](xs []T, ps []float64, opts *Options) ([]float64, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	out := make([]float64, len(ps))
+	a, err := clean(xs, opts.NaN)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		for i := range out {
+			out[i] = math.NaN()
+		}
+		return out, nil
+	}
+	slices.Sort(a)
+
+	for i, p := range ps {
+		h, err := rank(len(a), p, opts.Method)
+		if err != nil {
+			return nil, err
+		}
+		lo := int(h)
+		out[i] = float64(a[lo])
+		if frac := h - float64(lo); frac > 0 {
+			out[i] = lerp(a[lo], a[lo+1], frac)
+		}
+	}
+	return out, nil
+}
+
+// Select returns the k-th smallest element of xs, k from 0, in expected O(n)
+// time. xs is not modified. NaNs are an error, use SelectInPlace to choose.
+func Select[T Number](xs []T, k int) (T, error) {
+	a, err := clean(xs, NaNError)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return SelectInPlace(a, k)
+}
+
+// SelectInPlace is Select reordering xs instead of copying it: afterwards xs[k]
+// is the k-th smallest, smaller elements are before it and larger ones after.
+// xs must not contain NaNs.
+func SelectInPlace[T Number](xs []T, k int) (T, error) {
+	if len(xs) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	if k < 0 || k >= len(xs) {
+		var zero T
+		return zero, fmt.Errorf("%w: rank %d of %d elements", ErrRange, k, len(xs))
+	}
+	selectK(xs, k)
+	return xs[k], nil
+}
+
+// clean copies xs applying the NaN policy. It returns a nil slice and no error
+// when the result is NaN.
+func clean[T Number](xs []T, nan NaNPolicy) ([]T, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	a := make([]T, 0, len(xs))
+	for _, x := range xs {
+		if x != x { // only NaN isn't equal to itself
+			switch nan {
+			case NaNSkip:
+				continue
+			case NaNPropagate:
+				return nil, nil
+			default:
+				return nil, ErrNaN
+			}
+		}
+		a = append(a, x)
+	}
+	if len(a) == 0 {
+		return nil, fmt.Errorf("%w: only NaNs", ErrEmpty)
+	}
+	return a, nil
+}
+
+// selectK moves the k-th smallest element of a to a[k] with a quickselect on
+// random pivots. Should the partitions keep coming out lopsided it switches
+// to median of medians pivots, which guarantee O(n) in the worst case.
+func selectK[T Number](a []T, k int) {
+	budget := 2 * bits.Len(uint(len(a)))
+	for len(a) > 1 {
+		if len(a) <= 16 {
+			insertionSort(a)
+			return
+		}
+
+		var pivot T
+		if budget > 0 {
+			pivot = a[rand.Intn(len(a))]
+		} else {
+			pivot = medianOfMedians(a)
+		}
+		lt, gt := partition(a, pivot)
+
+		n := len(a)
+		switch {
+		case k < lt:
+			a = a[:lt]
+		case k >= gt:
+			a, k = a[gt:], k-gt
+		default:
+			return // a[k] equals the pivot
+		}
+		if len(a) > n*3/4 {
+			budget--
+		}
+	}
+}
+
+// partition reorders a into the elements less than, equal to and greater
+// than pivot, and returns the bounds of the equal ones: a[lt:gt].
+func partition[T Number](a []T, pivot T) (lt, gt int) {
+	i := 0
+	lt, gt = 0, len(a)
+	for i < gt {
+		switch {
+		case a[i] < pivot:
+			a[lt], a[i] = a[i], a[lt]
+			lt++
+			i++
+		case a[i] > pivot:
+			gt--
+			a[gt], a[i] = a[i], a[gt]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// medianOfMedians returns a pivot with at least 30% of a on each side of it.
+// It reorders a.
+func medianOfMedians[T Number](a []T) T {
+	m := 0
+	for i := 0; i < len(a); i += 5 {
+		g := a[i:min(i+5, len(a))]
+		insertionSort(g)
+		a[m], g[len(g)/2] = g[len(g)/2], a[m]
+		m++
+	}
+	selectK(a[:m], m/2)
+	return a[m/2]
+}
+
+func insertionSort[T Number](a []T) {
+	for i := 1; i < len(a); i++ {
+		for j := i; j > 0 && a[j] < a[j-1]; j-- {
+			a[j], a[j-1] = a[j-1], a[j]
+		}
+	}
+}
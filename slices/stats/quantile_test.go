@@ -0,0 +1,216 @@
+package stats_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"main/slices/stats"
+)
+
+// TestQuantileMethods compares with R's quantile(1:4, p, type = 7, 5 and 1).
+func TestQuantileMethods(t *testing.T) {
+	xs := []int{4, 2, 1, 3}
+	for _, c := range []struct {
+		method stats.Method
+		p      float64
+		want   float64
+	}{
+		{stats.R7, 0.25, 1.75},
+		{stats.R7, 0.5, 2.5},
+		{stats.R7, 0.9, 3.7},
+		{stats.Hazen, 0.25, 1.5},
+		{stats.Hazen, 0.5, 2.5},
+		{stats.Hazen, 0.9, 4},
+		{stats.NearestRank, 0.25, 1},
+		{stats.NearestRank, 0.5, 2},
+		{stats.NearestRank, 0.9, 4},
+	} {
+		got, err := stats.Quantile(xs, c.p, &stats.Options{Method: c.method})
+		if err != nil || got != c.want {
+			t.Errorf("%v quantile %v = %v, %v, want %v", c.method, c.p, got, err, c.want)
+		}
+	}
+}
+
+// inputs returns the data sets the selection is checked on: random, with
+// many duplicates, all equal, and the sorted, reversed and organ-pipe orders
+// which defeat naive pivots.
+func inputs(r *rand.Rand, n int) map[string][]float64 {
+	in := map[string][]float64{
+		"random":     make([]float64, n),
+		"duplicates": make([]float64, n),
+		"equal":      make([]float64, n),
+		"sorted":     make([]float64, n),
+		"reversed":   make([]float64, n),
+		"organ-pipe": make([]float64, n),
+	}
+	for i := 0; i < n; i++ {
+		in["random"][i] = r.NormFloat64()
+		in["duplicates"][i] = float64(r.Intn(5))
+		in["equal"][i] = 7
+		in["sorted"][i] = float64(i)
+		in["reversed"][i] = float64(n - i)
+		in["organ-pipe"][i] = float64(min(i, n-1-i))
+	}
+	return in
+}
+
+var sizes = []int{1, 2, 15, 16, 17, 100, 1000, 10_000}
+
+// TestSelect checks Select and SelectInPlace against a sorted copy, past the
+// insertion sort cutoff where the partitions run.
+func TestSelect(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range sizes {
+		for name, xs := range inputs(r, n) {
+			sorted := slices.Clone(xs)
+			slices.Sort(sorted)
+			orig := slices.Clone(xs)
+
+			for _, k := range []int{0, n / 4, n / 2, n - 1, r.Intn(n)} {
+				got, err := stats.Select(xs, k)
+				if err != nil || got != sorted[k] {
+					t.Fatalf("%s n=%d: Select(%d) = %v, %v, want %v", name, n, k, got, err, sorted[k])
+				}
+				if !slices.Equal(xs, orig) {
+					t.Fatalf("%s n=%d: Select(%d) modified its input", name, n, k)
+				}
+
+				a := slices.Clone(xs)
+				got, err = stats.SelectInPlace(a, k)
+				if err != nil || got != sorted[k] {
+					t.Fatalf("%s n=%d: SelectInPlace(%d) = %v, %v, want %v", name, n, k, got, err, sorted[k])
+				}
+				for i, x := range a {
+					if i < k && x > got || i > k && x < got {
+						t.Fatalf("%s n=%d: SelectInPlace(%d) left %v at %d", name, n, k, x, i)
+					}
+				}
+			}
+		}
+	}
+}
+
+// sortedQuantile is the R-7 quantile computed from sorted data.
+func sortedQuantile(sorted []float64, p float64) float64 {
+	h := float64(len(sorted)-1) * p
+	lo := int(h)
+	if lo == len(sorted)-1 {
+		return sorted[lo]
+	}
+	return sorted[lo] + (h-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+// TestQuantileRandom checks Quantile and Quantiles against a sorted copy.
+func TestQuantileRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	ps := []float64{0, 0.01, 0.25, 0.5, 0.9, 0.999, 1}
+	for _, n := range sizes {
+		for name, xs := range inputs(r, n) {
+			sorted := slices.Clone(xs)
+			slices.Sort(sorted)
+			orig := slices.Clone(xs)
+
+			all, err := stats.Quantiles(xs, ps, nil)
+			if err != nil {
+				t.Fatalf("%s n=%d: Quantiles: %v", name, n, err)
+			}
+			for i, p := range ps {
+				want := sortedQuantile(sorted, p)
+				got, err := stats.Quantile(xs, p, nil)
+				if err != nil || got != want {
+					t.Errorf("%s n=%d: Quantile(%v) = %v, %v, want %v", name, n, p, got, err, want)
+				}
+				if all[i] != want {
+					t.Errorf("%s n=%d: Quantiles[%v] = %v, want %v", name, n, p, all[i], want)
+				}
+			}
+			if !slices.Equal(xs, orig) {
+				t.Fatalf("%s n=%d: the input was modified", name, n)
+			}
+		}
+	}
+}
+
+func TestQuantileNaN(t *testing.T) {
+	nan := math.NaN()
+	xs := []float64{3, nan, 1, 2, nan}
+
+	if _, err := stats.Quantile(xs, 0.5, nil); !errors.Is(err, stats.ErrNaN) {
+		t.Errorf("NaNError: err = %v, want ErrNaN", err)
+	}
+	if got, err := stats.Quantile(xs, 0.5, &stats.Options{NaN: stats.NaNSkip}); err != nil || got != 2 {
+		t.Errorf("NaNSkip: median = %v, %v, want 2", got, err)
+	}
+	if got, err := stats.Quantile(xs, 0.5, &stats.Options{NaN: stats.NaNPropagate}); err != nil || !math.IsNaN(got) {
+		t.Errorf("NaNPropagate: median = %v, %v, want NaN", got, err)
+	}
+	if _, err := stats.Quantile([]float64{nan, nan}, 0.5, &stats.Options{NaN: stats.NaNSkip}); !errors.Is(err, stats.ErrEmpty) {
+		t.Errorf("NaNSkip of only NaNs: err = %v, want ErrEmpty", err)
+	}
+
+	got, err := stats.Quantiles(xs, []float64{0, 1}, &stats.Options{NaN: stats.NaNSkip})
+	if err != nil || !slices.Equal(got, []float64{1, 3}) {
+		t.Errorf("NaNSkip: Quantiles = %v, %v, want [1 3]", got, err)
+	}
+	got, err = stats.Quantiles(xs, []float64{0, 1}, &stats.Options{NaN: stats.NaNPropagate})
+	if err != nil || len(got) != 2 || !math.IsNaN(got[0]) || !math.IsNaN(got[1]) {
+		t.Errorf("NaNPropagate: Quantiles = %v, %v, want [NaN NaN]", got, err)
+	}
+	if _, err := stats.Select(xs, 0); !errors.Is(err, stats.ErrNaN) {
+		t.Errorf("Select: err = %v, want ErrNaN", err)
+	}
+}
+
+func TestQuantileErrors(t *testing.T) {
+	xs := []int{1, 2, 3}
+	if _, err := stats.Quantile([]int{}, 0.5, nil); !errors.Is(err, stats.ErrEmpty) {
+		t.Errorf("empty: err = %v, want ErrEmpty", err)
+	}
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := stats.Quantile(xs, p, nil); !errors.Is(err, stats.ErrRange) {
+			t.Errorf("Quantile(%v): err = %v, want ErrRange", p, err)
+		}
+	}
+	if _, err := stats.Quantiles(xs, []float64{0.5, 2}, nil); !errors.Is(err, stats.ErrRange) {
+		t.Errorf("Quantiles: err = %v, want ErrRange", err)
+	}
+	for _, k := range []int{-1, 3} {
+		if _, err := stats.Select(xs, k); !errors.Is(err, stats.ErrRange) {
+			t.Errorf("Select(%d): err = %v, want ErrRange", k, err)
+		}
+	}
+}
+
+// TestMedianOfMedians checks the worst case pivot leaves at least 30% of
+// the data on each side, which random pivots rarely make Select reach.
+func TestMedianOfMedians(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for _, n := range sizes[5:] { // the bound is asymptotic, small inputs miss it
+		for name, xs := range inputs(r, n) {
+			a := slices.Clone(xs)
+			pivot := stats.MedianOfMedians(a)
+			lt, gt := 0, 0
+			for _, x := range xs {
+				if x < pivot {
+					lt++
+				} else if x > pivot {
+					gt++
+				}
+			}
+			if lt > n*7/10 || gt > n*7/10 {
+				t.Errorf("%s n=%d: pivot %v has %d smaller and %d larger", name, n, pivot, lt, gt)
+			}
+			sorted := slices.Clone(xs)
+			slices.Sort(a)
+			slices.Sort(sorted)
+			if !slices.Equal(a, sorted) {
+				t.Errorf("%s n=%d: medianOfMedians lost elements", name, n)
+			}
+		}
+	}
+}
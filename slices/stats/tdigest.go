@@ -0,0 +1,222 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TDigest estimates any quantile of a stream with Dunning's merging t-digest:
+// the observations are clustered into centroids, small near the extremes and
+// large near the median, so tail quantiles like p99 stay accurate while the
+// memory is bounded by the compression, which caps the number of centroids.
+// Digests of different streams merge into the digest of the combined stream.
+//
+// A TDigest is not safe for concurrent use.
+type TDigest struct {
+	compression float64
+	centroids   []centroid // sorted by mean
+	buf         []centroid // unmerged observations
+	count       float64    // total weight, buf included
+	min, max    float64
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+// DefaultCompression keeps rank errors around 0.1% at the median and less in
+// the tails, with digests of about a kilobyte encoded.
+const DefaultCompression = 100
+
+// maxCompression bounds the compression of a decoded digest, a corrupt one
+// would have NewTDigest allocate without limit.
+const maxCompression = 100_000
+
+// NewTDigest returns an empty digest, compression is DefaultCompression when <= 0.
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+	return &TDigest{
+		compression: compression,
+		buf:         make([]centroid, 0, int(5*compression)),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Count returns the number of observations added, NaNs excluded.
+func (t *TDigest) Count() int64 { return int64(t.count) }
+
+// Add adds an observation. NaNs are ignored.
+func (t *TDigest) Add(x float64) {
+	t.add(x, 1)
+}
+
+func (t *TDigest) add(x, w float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	if len(t.buf) == cap(t.buf) {
+		t.compress()
+	}
+	t.buf = append(t.buf, centroid{x, w})
+	t.count += w
+	t.min = math.Min(t.min, x)
+	t.max = math.Max(t.max, x)
+}
+
+// Merge adds the observations summarized by o to t. o is not modified.
+func (t *TDigest) Merge(o *TDigest) {
+	for _, c := range o.centroids {
+		t.add(c.mean, c.weight)
+	}
+	for _, c := range o.buf {
+		t.add(c.mean, c.weight)
+	}
+	t.compress()
+}
+
+// compress merges the buffer into the centroids. A run of neighbours is merged
+// into one centroid while it spans less than one unit of the k1 scale
+// function, k(q) = compression/2π · asin(2q-1), which is steep at the tails.
+func (t *TDigest) compress() {
+	if len(t.buf) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buf...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	out := make([]centroid, 0, len(t.centroids)+1)
+	cur := all[0]
+	var before float64 // weight of the centroids already in out
+	limit := t.count * t.kInv(t.k(0)+1)
+	for _, c := range all[1:] {
+		if before+cur.weight+c.weight <= limit {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		out = append(out, cur)
+		before += cur.weight
+		limit = t.count * t.kInv(t.k(before/t.count)+1)
+		cur = c
+	}
+	t.centroids = append(out, cur)
+	t.buf = t.buf[:0]
+}
+
+func (t *TDigest) k(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (t *TDigest) kInv(k float64) float64 {
+	if k >= t.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/t.compression) + 1) / 2
+}
+
+// Quantile returns the estimate of the q-quantile, q in [0, 1],
+// NaN for an empty digest or a q out of range.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+	cs := t.centroids
+	if len(cs) == 1 {
+		return cs[0].mean
+	}
+
+	// each centroid is taken to sit at the middle of the ranks it covers, the
+	// quantile is interpolated between the two around its rank, or between the
+	// outer ones and the extremes
+	index := q * t.count
+	center := cs[0].weight / 2
+	if index <= center {
+		return t.min + (cs[0].mean-t.min)*index/center
+	}
+	for i := 0; i < len(cs)-1; i++ {
+		next := center + (cs[i].weight+cs[i+1].weight)/2
+		if index <= next {
+			return cs[i].mean + (cs[i+1].mean-cs[i].mean)*(index-center)/(next-center)
+		}
+		center = next
+	}
+	last := cs[len(cs)-1]
+	if rest := t.count - center; rest > 0 {
+		return last.mean + (t.max-last.mean)*math.Min((index-center)/rest, 1)
+	}
+	return t.max
+}
+
+const tdigestVersion = 1
+
+// MarshalBinary encodes t, little endian: a version byte, the compression,
+// min, max and the number of centroids, then the mean and weight of each.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+	var b bytes.Buffer
+	b.WriteByte(tdigestVersion)
+	binary.Write(&b, binary.LittleEndian, [3]float64{t.compression, t.min, t.max})
+	binary.Write(&b, binary.LittleEndian, uint32(len(t.centroids)))
+	for _, c := range t.centroids {
+		binary.Write(&b, binary.LittleEndian, [2]float64{c.mean, c.weight})
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes what MarshalBinary encoded into t.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if v, err := r.ReadByte(); err != nil || v != tdigestVersion {
+		return errors.New("stats: t-digest encoding: unknown version")
+	}
+	var head [3]float64
+	var n uint32
+	if err := binary.Read(r, binary.LittleEndian, &head); err != nil {
+		return fmt.Errorf("stats: t-digest encoding: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return fmt.Errorf("stats: t-digest encoding: %w", err)
+	}
+	if int64(n)*16 != int64(r.Len()) {
+		return fmt.Errorf("stats: t-digest encoding: %d centroids in %d bytes", n, r.Len())
+	}
+
+	// NewTDigest allocates a buffer of 5·compression centroids
+	if c := head[0]; !(c > 0 && c <= maxCompression) {
+		return fmt.Errorf("stats: t-digest encoding: compression %v out of (0, %d]", c, maxCompression)
+	}
+
+	d := NewTDigest(head[0])
+	d.min, d.max = head[1], head[2]
+	if n > 0 && !(d.min <= d.max) {
+		return fmt.Errorf("stats: t-digest encoding: min %v above max %v", d.min, d.max)
+	}
+	d.centroids = make([]centroid, n)
+	for i := range d.centroids {
+		var mw [2]float64
+		if err := binary.Read(r, binary.LittleEndian, &mw); err != nil {
+			return fmt.Errorf("stats: t-digest encoding: %w", err)
+		}
+		c := centroid{mw[0], mw[1]}
+		switch {
+		case !(c.weight > 0) || math.IsInf(c.weight, 1):
+			return fmt.Errorf("stats: t-digest encoding: centroid %d has weight %v", i, c.weight)
+		case !(c.mean >= d.min && c.mean <= d.max):
+			return fmt.Errorf("stats: t-digest encoding: centroid %d mean %v out of [%v, %v]", i, c.mean, d.min, d.max)
+		case i > 0 && c.mean < d.centroids[i-1].mean:
+			return fmt.Errorf("stats: t-digest encoding: centroid %d out of order", i)
+		}
+		d.centroids[i] = c
+		d.count += c.weight
+	}
+	*t = *d
+	return nil
+}
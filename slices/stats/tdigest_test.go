@@ -0,0 +1,117 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"main/slices/stats"
+)
+
+var tdigestQuantiles = []float64{0.01, 0.5, 0.9, 0.99, 0.999}
+
+func TestTDigestRankError(t *testing.T) {
+	const maxErr = 0.01
+	for d, ds := range datasets {
+		data, sorted := dataset(d, 50_000)
+		td := stats.NewTDigest(stats.DefaultCompression)
+		for _, x := range data {
+			td.Add(x)
+		}
+		for _, q := range tdigestQuantiles {
+			if re := rankError(sorted, q, td.Quantile(q)); re > maxErr {
+				t.Errorf("%s p%g: estimate %g has a rank error of %.3f%%", ds.name, q*100, td.Quantile(q), re*100)
+			}
+		}
+	}
+}
+
+// TestTDigestMergeBinary merges the digests of shards, then checks the
+// estimates of the merged digest once it went through its binary encoding.
+func TestTDigestMergeBinary(t *testing.T) {
+	const shards, maxErr = 8, 0.01
+	for d, ds := range datasets {
+		data, sorted := dataset(d, 50_000)
+		merged := stats.NewTDigest(stats.DefaultCompression)
+		for s := 0; s < shards; s++ {
+			part := stats.NewTDigest(stats.DefaultCompression)
+			for i := s; i < len(data); i += shards {
+				part.Add(data[i])
+			}
+			merged.Merge(part)
+		}
+
+		b, err := merged.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded stats.TDigest
+		if err := decoded.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Count() != int64(len(data)) {
+			t.Fatalf("%s: decoded count %d, want %d", ds.name, decoded.Count(), len(data))
+		}
+		for _, q := range tdigestQuantiles {
+			if decoded.Quantile(q) != merged.Quantile(q) {
+				t.Errorf("%s p%g: decoded %g, merged %g", ds.name, q*100, decoded.Quantile(q), merged.Quantile(q))
+			}
+			if re := rankError(sorted, q, decoded.Quantile(q)); re > maxErr {
+				t.Errorf("%s p%g: merged estimate %g has a rank error of %.3f%%", ds.name, q*100, decoded.Quantile(q), re*100)
+			}
+		}
+	}
+}
+
+// TestTDigestSmall checks that a digest of a few values is exact at the extremes.
+func TestTDigestSmall(t *testing.T) {
+	td := stats.NewTDigest(stats.DefaultCompression)
+	for _, x := range []float64{3, 1, 2} {
+		td.Add(x)
+	}
+	if lo, hi := td.Quantile(0), td.Quantile(1); lo != 1 || hi != 3 {
+		t.Errorf("extremes %v %v, want 1 3", lo, hi)
+	}
+}
+
+// TestTDigestBinaryCorrupt checks that an encoding a TDigest can't be in is
+// refused. The layout is a version byte, the compression at 1, min at 9, max
+// at 17, the number of centroids at 25, then mean and weight pairs from 29.
+func TestTDigestBinaryCorrupt(t *testing.T) {
+	td := stats.NewTDigest(stats.DefaultCompression)
+	for _, x := range []float64{1, 2, 3, 4} {
+		td.Add(x)
+	}
+	b, _ := td.MarshalBinary()
+	mean := func(i int) int { return 29 + 16*i }
+	weight := func(i int) int { return 37 + 16*i }
+
+	for _, c := range []struct {
+		name string
+		b    []byte
+	}{
+		{"huge compression", patch(b, 1, math.Float64bits(1e18))},
+		{"NaN compression", patch(b, 1, math.Float64bits(math.NaN()))},
+		{"zero compression", patch(b, 1, 0)},
+		{"negative compression", patch(b, 1, math.Float64bits(-5))},
+		{"min above max", patch(b, 9, math.Float64bits(10))},
+		{"NaN max", patch(b, 17, math.Float64bits(math.NaN()))},
+		{"zero weight", patch(b, weight(1), 0)},
+		{"negative weight", patch(b, weight(2), math.Float64bits(-1))},
+		{"NaN weight", patch(b, weight(0), math.Float64bits(math.NaN()))},
+		{"unsorted means", patch(b, mean(1), math.Float64bits(3.5))},
+		{"mean out of range", patch(b, mean(3), math.Float64bits(5))},
+		{"truncated", b[:len(b)-1]},
+	} {
+		var d stats.TDigest
+		if err := d.UnmarshalBinary(c.b); err == nil {
+			t.Errorf("%s: decoded a digest of %d", c.name, d.Count())
+		}
+	}
+
+	// an empty digest has min +Inf and max -Inf
+	empty, _ := stats.NewTDigest(0).MarshalBinary()
+	var d stats.TDigest
+	if err := d.UnmarshalBinary(empty); err != nil || d.Count() != 0 {
+		t.Errorf("decoding an empty digest: %d, %v", d.Count(), err)
+	}
+}
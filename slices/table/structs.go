@@ -0,0 +1,154 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromStructs builds a table from a slice of structs or of pointers to
+// structs, one column per exported field. The table struct tag tunes a column:
+//
+//	type Repo struct {
+//		Name  string  `table:"Repository"`
+//		Stars int     `table:",align=right"`
+//		Score float64 `table:"Score,format=%.2f,width=8"`
+//		URL   string  `table:"-"` // skipped
+//	}
+//
+// The options are align (left, right or center, right by default for numbers),
+// width (see Column.MaxWidth) and format (a fmt verb for the value, or a
+// layout for a time.Time).
+// Fields of embedded structs are promoted like in Go.
+func FromStructs(rows any) (*Table, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("table: FromStructs of %T, want a slice", rows)
+	}
+	et := v.Type().Elem()
+	if et.Kind() == reflect.Pointer {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("table: FromStructs of %T, want a slice of structs", rows)
+	}
+
+	type field struct {
+		index  []int
+		format string
+	}
+	var fields []field
+	t := New()
+	for _, f := range reflect.VisibleFields(et) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("table")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		col := Column{Header: name}
+		if col.Header == "" {
+			col.Header = f.Name
+		}
+		if isNumber(f.Type) {
+			col.Align = Right
+		}
+
+		fd := field{index: f.Index}
+		for _, opt := range strings.Split(opts, ",") {
+			if opt == "" {
+				continue
+			}
+			k, val, _ := strings.Cut(opt, "=")
+			switch k {
+			case "align":
+				a, err := parseAlign(val)
+				if err != nil {
+					return nil, fmt.Errorf("table: field %s: %w", f.Name, err)
+				}
+				col.Align = a
+			case "width":
+				n, err := strconv.Atoi(val)
+				if err != nil {
+					return nil, fmt.Errorf("table: field %s: width: %w", f.Name, err)
+				}
+				col.MaxWidth = n
+			case "format":
+				fd.format = val
+			default:
+				return nil, fmt.Errorf("table: field %s: unknown option %q", f.Name, k)
+			}
+		}
+		t.Columns = append(t.Columns, col)
+		fields = append(fields, fd)
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if e.Kind() == reflect.Pointer {
+			if e.IsNil() {
+				t.Rows = append(t.Rows, make([]string, len(fields)))
+				continue
+			}
+			e = e.Elem()
+		}
+		row := make([]string, len(fields))
+		for c, fd := range fields {
+			fv, err := e.FieldByIndexErr(fd.index)
+			if err != nil {
+				continue // through a nil embedded pointer, leave it empty
+			}
+			row[c] = format(fv, fd.format)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, nil
+}
+
+func format(v reflect.Value, verb string) string {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+	}
+	if t, ok := v.Interface().(time.Time); ok && verb != "" {
+		return t.Format(verb)
+	}
+	if verb != "" {
+		return fmt.Sprintf(verb, v.Interface())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func isNumber(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func parseAlign(s string) (Align, error) {
+	switch s {
+	case "left":
+		return Left, nil
+	case "right":
+		return Right, nil
+	case "center":
+		return Center, nil
+	}
+	return Left, errors.New("align must be left, right or center, got " + strconv.Quote(s))
+}
@@ -0,0 +1,39 @@
+package table
+
+// Style is how the borders of a table are drawn.
+type Style struct {
+	// H draws the horizontal rules, there are none when it is empty.
+	H string
+	// V is drawn at both ends of a line, Sep between cells, Pad on both sides of a cell.
+	V, Sep, Pad string
+
+	TopLeft, TopMid, TopRight          string
+	MidLeft, MidMid, MidRight          string // under the header
+	BottomLeft, BottomMid, BottomRight string
+
+	markdown bool
+}
+
+var (
+	// ASCII draws the borders with + - and |, like the slices/slices.go table.
+	ASCII = Style{
+		H: "-", V: "|", Sep: "|", Pad: " ",
+		TopLeft: "+", TopMid: "+", TopRight: "+",
+		MidLeft: "+", MidMid: "+", MidRight: "+",
+		BottomLeft: "+", BottomMid: "+", BottomRight: "+",
+	}
+
+	// Box draws the borders with the Unicode box-drawing characters.
+	Box = Style{
+		H: "─", V: "│", Sep: "│", Pad: " ",
+		TopLeft: "┌", TopMid: "┬", TopRight: "┐",
+		MidLeft: "├", MidMid: "┼", MidRight: "┤",
+		BottomLeft: "└", BottomMid: "┴", BottomRight: "┘",
+	}
+
+	// Markdown renders a GitHub flavored Markdown table.
+	Markdown = Style{markdown: true}
+
+	// Plain has no borders, the columns are separated by two spaces.
+	Plain = Style{Sep: "  "}
+)
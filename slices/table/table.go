@@ -0,0 +1,241 @@
+// Package table renders text tables: column widths computed from the display
+// width of the cells, per column alignment, wrapping, and ASCII, box-drawing,
+// Markdown or plain styles. It also writes CSV and TSV, and builds tables from
+// slices of structs.
+//
+// It grew out of the length and capacity table of slices/slices.go:
+//
+//	t := table.New("Slice", "Length", "Capacity")
+//	t.AddRow("sl2", len(sl2), cap(sl2))
+//	t.Render(os.Stdout)
+package table
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Align is the horizontal alignment of a column.
+type Align int
+
+const (
+	Left Align = iota
+	Right
+	Center
+)
+
+// Column describes a column of a Table.
+type Column struct {
+	Header string
+	Align  Align
+	// MaxWidth wraps the cells wider than it, in display columns; 0 means no limit.
+	MaxWidth int
+}
+
+// Table is a header and rows of cells. Rows shorter than the header are
+// padded with empty cells, longer ones are cut.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	Style   Style
+}
+
+// New returns a table with the given headers, left aligned, in the ASCII style.
+func New(headers ...string) *Table {
+	t := &Table{Style: ASCII}
+	for _, h := range headers {
+		t.Columns = append(t.Columns, Column{Header: h})
+	}
+	return t
+}
+
+// SetAlign sets the alignment of the columns cols.
+func (t *Table) SetAlign(a Align, cols ...int) *Table {
+	for _, c := range cols {
+		t.Columns[c].Align = a
+	}
+	return t
+}
+
+// AddRow appends a row, formatting the cells with fmt.Sprint.
+func (t *Table) AddRow(cells ...any) *Table {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = fmt.Sprint(c)
+	}
+	t.Rows = append(t.Rows, row)
+	return t
+}
+
+// cell returns the cell of row in column c.
+func cell(row []string, c int) string {
+	if c < len(row) {
+		return row[c]
+	}
+	return ""
+}
+
+// String renders t.
+func (t *Table) String() string {
+	var b strings.Builder
+	t.Render(&b)
+	return b.String()
+}
+
+// Render writes t in t.Style. A table without columns writes nothing.
+func (t *Table) Render(w io.Writer) error {
+	if len(t.Columns) == 0 {
+		return nil
+	}
+	if t.Style.markdown {
+		return t.renderMarkdown(w)
+	}
+
+	// lines[r][c] holds the wrapped lines of a cell, row 0 is the header
+	lines := make([][][]string, 0, len(t.Rows)+1)
+	widths := make([]int, len(t.Columns))
+	addRow := func(row []string) {
+		cells := make([][]string, len(t.Columns))
+		for c, col := range t.Columns {
+			cells[c] = wrap(cell(row, c), col.MaxWidth)
+			for _, l := range cells[c] {
+				widths[c] = max(widths[c], Width(l))
+			}
+		}
+		lines = append(lines, cells)
+	}
+	header := make([]string, len(t.Columns))
+	for c, col := range t.Columns {
+		header[c] = col.Header
+	}
+	addRow(header)
+	for _, row := range t.Rows {
+		addRow(row)
+	}
+
+	s := t.Style
+	var b strings.Builder
+	rule := func(left, mid, right string) {
+		if s.H == "" {
+			return
+		}
+		b.WriteString(left)
+		for c, w := range widths {
+			if c > 0 {
+				b.WriteString(mid)
+			}
+			b.WriteString(strings.Repeat(s.H, w+2*len(s.Pad)))
+		}
+		b.WriteString(right)
+		b.WriteByte('\n')
+	}
+
+	rule(s.TopLeft, s.TopMid, s.TopRight)
+	for r, cells := range lines {
+		height := 0
+		for _, c := range cells {
+			height = max(height, len(c))
+		}
+		for l := 0; l < height; l++ {
+			var line strings.Builder
+			line.WriteString(s.V)
+			for c, cl := range cells {
+				if c > 0 {
+					line.WriteString(s.Sep)
+				}
+				text := ""
+				if l < len(cl) {
+					text = cl[l]
+				}
+				line.WriteString(s.Pad + pad(text, widths[c], t.Columns[c].Align) + s.Pad)
+			}
+			line.WriteString(s.V)
+			b.WriteString(strings.TrimRight(line.String(), " "))
+			b.WriteByte('\n')
+		}
+		if r == 0 {
+			rule(s.MidLeft, s.MidMid, s.MidRight)
+		}
+	}
+	rule(s.BottomLeft, s.BottomMid, s.BottomRight)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderMarkdown writes a GitHub flavored Markdown table. Cells aren't
+// wrapped, their line breaks become <br>.
+func (t *Table) renderMarkdown(w io.Writer) error {
+	esc := strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+	var b strings.Builder
+	row := func(cells func(c int) string) {
+		b.WriteString("|")
+		for c := range t.Columns {
+			b.WriteString(" " + esc.Replace(cells(c)) + " |")
+		}
+		b.WriteByte('\n')
+	}
+
+	row(func(c int) string { return t.Columns[c].Header })
+	row(func(c int) string {
+		switch t.Columns[c].Align {
+		case Right:
+			return "---:"
+		case Center:
+			return ":---:"
+		}
+		return "---"
+	})
+	for _, r := range t.Rows {
+		row(func(c int) string { return cell(r, c) })
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCSV writes the header and the rows as CSV.
+func (t *Table) WriteCSV(w io.Writer) error {
+	return t.writeDelimited(w, ',')
+}
+
+// WriteTSV writes the header and the rows as tab separated values.
+// Cells holding a tab, a quote or a line break are quoted as in CSV.
+func (t *Table) WriteTSV(w io.Writer) error {
+	return t.writeDelimited(w, '\t')
+}
+
+func (t *Table) writeDelimited(w io.Writer, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	record := make([]string, len(t.Columns))
+	for c, col := range t.Columns {
+		record[c] = col.Header
+	}
+	cw.Write(record)
+	for _, row := range t.Rows {
+		for c := range t.Columns {
+			record[c] = cell(row, c)
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// pad aligns s in width display columns.
+func pad(s string, width int, a Align) string {
+	gap := width - Width(s)
+	if gap <= 0 {
+		return s
+	}
+	switch a {
+	case Right:
+		return strings.Repeat(" ", gap) + s
+	case Center:
+		return strings.Repeat(" ", gap/2) + s + strings.Repeat(" ", gap-gap/2)
+	}
+	return s + strings.Repeat(" ", gap)
+}
@@ -0,0 +1,265 @@
+package table
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// sample has a right aligned column, a centered one wrapping at 10 columns,
+// wide runes and a short row.
+func sample(s Style) *Table {
+	t := New("Name", "Stars", "Note").SetAlign(Right, 1).SetAlign(Center, 2)
+	t.Columns[2].MaxWidth = 10
+	t.AddRow("slices", 1234, "a growing note that wraps")
+	t.AddRow("日本", 7, "a|b")
+	t.AddRow("x")
+	t.Style = s
+	return t
+}
+
+func TestRender(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		style Style
+		want  string
+	}{
+		{"ascii", ASCII, `+--------+-------+-----------+
+| Name   | Stars |   Note    |
++--------+-------+-----------+
+| slices |  1234 | a growing |
+|        |       | note that |
+|        |       |   wraps   |
+| 日本   |     7 |    a|b    |
+| x      |       |           |
++--------+-------+-----------+
+`},
+		{"box", Box, `┌────────┬───────┬───────────┐
+│ Name   │ Stars │   Note    │
+├────────┼───────┼───────────┤
+│ slices │  1234 │ a growing │
+│        │       │ note that │
+│        │       │   wraps   │
+│ 日本   │     7 │    a|b    │
+│ x      │       │           │
+└────────┴───────┴───────────┘
+`},
+		{"markdown", Markdown, `| Name | Stars | Note |
+| --- | ---: | :---: |
+| slices | 1234 | a growing note that wraps |
+| 日本 | 7 | a\|b |
+| x |  |  |
+`},
+		{"plain", Plain, `Name    Stars    Note
+slices   1234  a growing
+               note that
+                 wraps
+日本        7     a|b
+x
+`},
+	} {
+		if got := sample(c.style).String(); got != c.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRenderNoColumns(t *testing.T) {
+	for _, s := range []Style{ASCII, Box, Markdown, Plain} {
+		tb := New()
+		tb.Style = s
+		tb.AddRow("ignored")
+		if got := tb.String(); got != "" {
+			t.Errorf("%q, want nothing", got)
+		}
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	tb := New("a|b", "c")
+	tb.Style = Markdown
+	tb.AddRow("x | y", "one\ntwo\r\nthree")
+	want := "| a\\|b | c |\n| --- | --- |\n| x \\| y | one<br>two<br>three |\n"
+	if got := tb.String(); got != want {
+		t.Errorf("%q, want %q", got, want)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"héllo", 5},
+		{"e\u0301", 1},      // combining acute accent
+		{"日本語", 6},          // CJK
+		{"한글", 4},           // precomposed Hangul
+		{"\u1100\u1161", 2}, // Hangul jamo, the vowel joins the initial
+		{"🚀", 2},
+		{"a\tb\x7f", 2}, // control characters
+		{"\u200d", 0},   // zero width joiner
+		{"ｈｉ", 4},       // fullwidth Latin
+	} {
+		if got := Width(c.s); got != c.want {
+			t.Errorf("Width(%q) = %d, want %d", c.s, got, c.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	for _, c := range []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"", 5, []string{""}},
+		{"short", 10, []string{"short"}},
+		{"no limit at all", 0, []string{"no limit at all"}},
+		{"one\ntwo\r\nthree", 0, []string{"one", "two", "three"}},
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"a  lot   of    spaces", 6, []string{"a lot", "of", "spaces"}},
+		{"supercalifragilistic", 8, []string{"supercal", "ifragili", "stic"}},
+		{"go supercalifragilistic", 8, []string{"go", "supercal", "ifragili", "stic"}},
+		{"日本語の文", 4, []string{"日本", "語の", "文"}},
+		{"日本語", 3, []string{"日", "本", "語"}},
+		{"日本", 1, []string{"日", "本"}}, // a wide rune wider than the line still gets one
+		{"ab日", 3, []string{"ab", "日"}},
+		{"   ", 2, []string{""}},
+	} {
+		if got := wrap(c.s, c.width); !slices.Equal(got, c.want) {
+			t.Errorf("wrap(%q, %d) = %q, want %q", c.s, c.width, got, c.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	for _, c := range []struct {
+		s     string
+		width int
+		a     Align
+		want  string
+	}{
+		{"ab", 5, Left, "ab   "},
+		{"ab", 5, Right, "   ab"},
+		{"ab", 5, Center, " ab  "}, // the odd space goes right
+		{"ab", 6, Center, "  ab  "},
+		{"日本", 6, Center, " 日本 "},
+		{"toolong", 3, Center, "toolong"},
+	} {
+		if got := pad(c.s, c.width, c.a); got != c.want {
+			t.Errorf("pad(%q, %d, %v) = %q, want %q", c.s, c.width, c.a, got, c.want)
+		}
+	}
+}
+
+func TestDelimited(t *testing.T) {
+	tb := New("name", "note")
+	tb.AddRow("plain", "a, b")
+	tb.AddRow("quote", `say "hi"`)
+	tb.AddRow("tab", "a\tb")
+	tb.AddRow("lines", "one\ntwo")
+	tb.AddRow("short")
+
+	var b strings.Builder
+	if err := tb.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "name,note\nplain,\"a, b\"\nquote,\"say \"\"hi\"\"\"\ntab,a\tb\nlines,\"one\ntwo\"\nshort,\n"
+	if b.String() != want {
+		t.Errorf("CSV = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	if err := tb.WriteTSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	want = "name\tnote\nplain\ta, b\nquote\t\"say \"\"hi\"\"\"\ntab\t\"a\tb\"\nlines\t\"one\ntwo\"\nshort\t\n"
+	if b.String() != want {
+		t.Errorf("TSV = %q, want %q", b.String(), want)
+	}
+}
+
+type Base struct {
+	ID      int
+	Created time.Time `table:"Created,format=2006-01-02"`
+}
+
+type repo struct {
+	*Base
+	Name   string  `table:"Repository"`
+	Stars  int     `table:",align=left"`
+	Score  float64 `table:"Score,format=%.2f,width=8"`
+	Owner  *string
+	URL    string `table:"-"`
+	hidden int
+}
+
+func TestFromStructs(t *testing.T) {
+	owner := "golang"
+	rows := []*repo{
+		{Base: &Base{ID: 1, Created: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, Name: "go", Stars: 120000, Score: 9.876, Owner: &owner, URL: "x"},
+		{Name: "orphan", Score: 1}, // nil embedded pointer
+		nil,
+	}
+	tb, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Column{
+		{Header: "ID", Align: Right},
+		{Header: "Created"},
+		{Header: "Repository"},
+		{Header: "Stars"},
+		{Header: "Score", Align: Right, MaxWidth: 8},
+		{Header: "Owner"},
+	}
+	if !slices.Equal(tb.Columns, want) {
+		t.Errorf("columns = %+v, want %+v", tb.Columns, want)
+	}
+	wantRows := [][]string{
+		{"1", "2024-03-01", "go", "120000", "9.88", "golang"},
+		{"", "", "orphan", "0", "1.00", ""},
+		{"", "", "", "", "", ""},
+	}
+	if !slices.EqualFunc(tb.Rows, wantRows, slices.Equal[[]string]) {
+		t.Errorf("rows = %q, want %q", tb.Rows, wantRows)
+	}
+
+	// values work as well as pointers
+	tb, err = FromStructs([]Base{{ID: 2}})
+	if err != nil || len(tb.Rows) != 1 || tb.Rows[0][0] != "2" {
+		t.Errorf("FromStructs of values = %v, %v", tb, err)
+	}
+}
+
+func TestFromStructsErrors(t *testing.T) {
+	type badAlign struct {
+		A int `table:",align=middle"`
+	}
+	type badWidth struct {
+		A int `table:",width=wide"`
+	}
+	type badOption struct {
+		A int `table:",colour=red"`
+	}
+	for _, c := range []struct {
+		rows any
+		want string
+	}{
+		{42, "table: FromStructs of int, want a slice"},
+		{[]int{1}, "table: FromStructs of []int, want a slice of structs"},
+		{[]badAlign{}, `table: field A: align must be left, right or center, got "middle"`},
+		{[]badWidth{}, `table: field A: width: strconv.Atoi: parsing "wide": invalid syntax`},
+		{[]badOption{}, `table: field A: unknown option "colour"`},
+	} {
+		_, err := FromStructs(c.rows)
+		if err == nil || err.Error() != c.want {
+			t.Errorf("FromStructs(%T) = %v, want %s", c.rows, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package table
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Width returns the number of terminal columns s takes: East Asian wide
+// characters and emoji take two, combining marks and control characters none.
+func Width(s string) int {
+	n := 0
+	for _, r := range s {
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// RuneWidth returns the number of terminal columns r takes.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300: // Latin, the common case
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0 // combining marks, zero width joiners, variation selectors
+	case r >= 0x1160 && r <= 0x11ff:
+		return 0 // Hangul medial vowels and final consonants join the initial
+	}
+	for _, w := range wide {
+		if r < w[0] {
+			break
+		}
+		if r <= w[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// wide are the ranges of East Asian Wide and Fullwidth characters and of the
+// emoji presented as wide, in order.
+var wide = [][2]rune{
+	{0x1100, 0x115f},   // Hangul initial consonants
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x23e9, 0x23ec},   // media controls
+	{0x23f0, 0x23f0},   // alarm clock
+	{0x23f3, 0x23f3},   // hourglass
+	{0x25fd, 0x25fe},   // squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x267f, 0x267f},   // wheelchair
+	{0x2693, 0x2693},   // anchor
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // circles
+	{0x26bd, 0x26be},   // balls
+	{0x26c4, 0x26c5},   // snowman, sun
+	{0x26ce, 0x26ce},   // ophiuchus
+	{0x26d4, 0x26d4},   // no entry
+	{0x26ea, 0x26ea},   // church
+	{0x26f2, 0x26f3},   // fountain, golf
+	{0x26f5, 0x26f5},   // sailboat
+	{0x26fa, 0x26fa},   // tent
+	{0x26fd, 0x26fd},   // fuel pump
+	{0x2705, 0x2705},   // check mark
+	{0x270a, 0x270b},   // fists
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x274e, 0x274e},   // cross mark
+	{0x2753, 0x2755},   // question marks
+	{0x2757, 0x2757},   // exclamation mark
+	{0x2795, 0x2797},   // plus, minus, division
+	{0x27b0, 0x27b0},   // curly loop
+	{0x27bf, 0x27bf},   // double curly loop
+	{0x2b1b, 0x2b1c},   // large squares
+	{0x2b50, 0x2b50},   // star
+	{0x2b55, 0x2b55},   // circle
+	{0x2e80, 0x303e},   // CJK radicals, punctuation
+	{0x3041, 0x33ff},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4dbf},   // CJK extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility forms, small forms
+	{0xff00, 0xff60},   // fullwidth forms
+	{0xffe0, 0xffe6},   // fullwidth signs
+	{0x16fe0, 0x16fe4}, // ideographic symbols
+	{0x17000, 0x18cff}, // Tangut
+	{0x1b000, 0x1b2ff}, // Kana supplement
+	{0x1f004, 0x1f004}, // mahjong tile
+	{0x1f0cf, 0x1f0cf}, // playing card
+	{0x1f18e, 0x1f18e}, // AB button
+	{0x1f191, 0x1f19a}, // squared words
+	{0x1f200, 0x1f251}, // enclosed ideographs
+	{0x1f300, 0x1f64f}, // pictographs, emoticons
+	{0x1f680, 0x1f6ff}, // transport and map symbols
+	{0x1f7e0, 0x1f7eb}, // colored circles and squares
+	{0x1f90c, 0x1f9ff}, // supplemental symbols and pictographs
+	{0x1fa70, 0x1faff}, // symbols and pictographs extended A
+	{0x20000, 0x2fffd}, // CJK extensions B to F
+	{0x30000, 0x3fffd}, // CJK extension G
+}
+
+// wrap breaks s into lines of at most width display columns, at spaces when
+// possible. Line breaks in s are kept. width <= 0 only splits the line breaks.
+func wrap(s string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if width <= 0 || Width(para) <= width {
+			lines = append(lines, para)
+			continue
+		}
+
+		start := len(lines)
+		var line strings.Builder
+		lw := 0
+		flush := func() {
+			lines = append(lines, line.String())
+			line.Reset()
+			lw = 0
+		}
+		for _, word := range strings.Fields(para) {
+			ww := Width(word)
+			if line.Len() > 0 && lw+1+ww <= width {
+				line.WriteByte(' ')
+				line.WriteString(word)
+				lw += 1 + ww
+				continue
+			}
+			if line.Len() > 0 {
+				flush()
+			}
+			// break the words longer than a line
+			for ww > width {
+				n, cw := 0, 0
+				for n < len(word) {
+					r, size := utf8.DecodeRuneInString(word[n:])
+					if cw+RuneWidth(r) > width && cw > 0 {
+						break
+					}
+					cw += RuneWidth(r)
+					n += size
+				}
+				lines = append(lines, word[:n])
+				word, ww = word[n:], ww-cw
+			}
+			line.WriteString(word)
+			lw = ww
+		}
+		if line.Len() > 0 || len(lines) == start {
+			flush()
+		}
+	}
+	return lines
+}
@@ -0,0 +1,215 @@
+package vector
+
+import "unsafe"
+
+// Deque is a double-ended queue of T in a circular buffer: pushing and
+// popping at either end is O(1), and so is indexing. When full it grows like
+// a Vector, by a Growth, unless it's a ring made by NewRing, which keeps its
+// capacity and overwrites its oldest elements instead.
+//
+// Once a Deque is as large as it gets, pushes and pops don't allocate. The
+// zero value is an empty Deque ready to use, growing by Doubling.
+type Deque[T any] struct {
+	buf    []T // len(buf) is the capacity
+	head   int // index in buf of the front element
+	n      int
+	growth Growth
+	stats  Stats
+	ring   bool
+}
+
+// NewDeque returns an empty Deque with room for capacity elements.
+func NewDeque[T any](capacity int) *Deque[T] {
+	return NewDequeWithGrowth[T](capacity, nil)
+}
+
+// NewDequeWithGrowth returns an empty Deque with room for capacity elements
+// that grows according to g, Doubling when nil.
+func NewDequeWithGrowth[T any](capacity int, g Growth) *Deque[T] {
+	d := &Deque[T]{growth: g}
+	if capacity > 0 {
+		d.realloc(capacity, capacity)
+	}
+	return d
+}
+
+// NewRing returns an empty Deque that holds at most capacity elements, for
+// "last N events" buffers: pushing at the back of a full ring drops the
+// front element, pushing at the front drops the back one. It panics if
+// capacity < 1.
+func NewRing[T any](capacity int) *Deque[T] {
+	if capacity < 1 {
+		panic("vector: NewRing capacity must be positive")
+	}
+	d := NewDeque[T](capacity)
+	d.ring = true
+	return d
+}
+
+// SetGrowth changes how d grows from now on, Doubling when g is nil.
+// It has no effect on a ring.
+func (d *Deque[T]) SetGrowth(g Growth) { d.growth = g }
+
+// Stats returns the allocation telemetry of d since it was created.
+func (d *Deque[T]) Stats() Stats { return d.stats }
+
+// Len returns the number of elements.
+func (d *Deque[T]) Len() int { return d.n }
+
+// Cap returns the number of elements d can hold without reallocating.
+func (d *Deque[T]) Cap() int { return len(d.buf) }
+
+// Full reports whether the next push reallocates, or overwrites in a ring.
+func (d *Deque[T]) Full() bool { return d.n == len(d.buf) }
+
+// index returns the index in d.buf of the element i.
+func (d *Deque[T]) index(i int) int {
+	j := d.head + i
+	if j >= len(d.buf) {
+		j -= len(d.buf)
+	}
+	return j
+}
+
+// PushBack adds x after the last element.
+func (d *Deque[T]) PushBack(x T) {
+	if d.Full() && d.ring {
+		d.buf[d.head] = x // the back wraps around onto the front
+		d.head = d.index(1)
+		return
+	}
+	d.grow()
+	d.buf[d.index(d.n)] = x
+	d.n++
+}
+
+// PushFront adds x before the first element.
+func (d *Deque[T]) PushFront(x T) {
+	if d.Full() && d.ring {
+		d.head = d.index(len(d.buf) - 1) // onto the back element
+		d.buf[d.head] = x
+		return
+	}
+	d.grow()
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = x
+	d.n++
+}
+
+// PopFront removes and returns the first element.
+func (d *Deque[T]) PopFront() (T, error) {
+	var zero T
+	if d.n == 0 {
+		return zero, ErrEmpty
+	}
+	x := d.buf[d.head]
+	d.buf[d.head] = zero // don't keep what it points to alive
+	d.head = d.index(1)
+	d.n--
+	return x, nil
+}
+
+// PopBack removes and returns the last element.
+func (d *Deque[T]) PopBack() (T, error) {
+	var zero T
+	if d.n == 0 {
+		return zero, ErrEmpty
+	}
+	j := d.index(d.n - 1)
+	x := d.buf[j]
+	d.buf[j] = zero
+	d.n--
+	return x, nil
+}
+
+// Front returns the first element.
+func (d *Deque[T]) Front() (T, error) {
+	if d.n == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return d.buf[d.head], nil
+}
+
+// Back returns the last element.
+func (d *Deque[T]) Back() (T, error) {
+	if d.n == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return d.buf[d.index(d.n-1)], nil
+}
+
+// Get returns the element at index i, 0 being the front.
+func (d *Deque[T]) Get(i int) (T, error) {
+	if i < 0 || i >= d.n {
+		var zero T
+		return zero, &IndexError{"Get", i, d.n}
+	}
+	return d.buf[d.index(i)], nil
+}
+
+// Set replaces the element at index i.
+func (d *Deque[T]) Set(i int, x T) error {
+	if i < 0 || i >= d.n {
+		return &IndexError{"Set", i, d.n}
+	}
+	d.buf[d.index(i)] = x
+	return nil
+}
+
+// Clear removes every element, keeping the capacity.
+func (d *Deque[T]) Clear() {
+	clear(d.buf)
+	d.head, d.n = 0, 0
+}
+
+// AppendTo appends the elements of d to dst, front to back, and returns the
+// extended slice, which doesn't share d's storage.
+func (d *Deque[T]) AppendTo(dst []T) []T {
+	end := d.head + d.n
+	if end <= len(d.buf) {
+		return append(dst, d.buf[d.head:end]...)
+	}
+	dst = append(dst, d.buf[d.head:]...)
+	return append(dst, d.buf[:end-len(d.buf)]...)
+}
+
+// All iterates over the indexes and elements of d, front to back, with the
+// shape of Vector.All.
+func (d *Deque[T]) All() func(yield func(int, T) bool) {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < d.n; i++ {
+			if !yield(i, d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// grow makes room for one more element, reallocating as d.growth decides.
+func (d *Deque[T]) grow() {
+	if !d.Full() {
+		return
+	}
+	g := d.growth
+	if g == nil {
+		g = Doubling
+	}
+	need := d.n + 1
+	d.realloc(max(g.Grow(len(d.buf), need, d.elemSize()), need), need)
+}
+
+// realloc moves the elements to the start of a new buffer of capacity elements.
+func (d *Deque[T]) realloc(capacity, need int) {
+	d.stats.record(len(d.buf), d.n, capacity, need, d.elemSize())
+
+	buf := make([]T, capacity)
+	d.AppendTo(buf[:0])
+	d.buf, d.head = buf, 0
+}
+
+func (d *Deque[T]) elemSize() uintptr {
+	var zero T
+	return unsafe.Sizeof(zero)
+}
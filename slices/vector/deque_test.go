@@ -0,0 +1,145 @@
+package vector_test
+
+import (
+	"errors"
+	"testing"
+
+	"main/slices/vector"
+)
+
+func TestDequeLikeSlice(t *testing.T) {
+	check(t, dequeLikeSlice)
+}
+
+// TestRingKeepsLast checks that a ring keeps the last pushed elements.
+func TestRingKeepsLast(t *testing.T) {
+	check(t, func(xs []int, n uint8) bool {
+		size := int(n%16) + 1
+		r := vector.NewRing[int](size)
+		for _, x := range xs {
+			r.PushBack(x)
+		}
+		want := xs[max(len(xs)-size, 0):]
+		return r.Cap() == size && equal(r.AppendTo(nil), want)
+	})
+}
+
+// TestRingPushFront checks that pushing at the front of a full ring drops the back.
+func TestRingPushFront(t *testing.T) {
+	r := vector.NewRing[int](3)
+	for i := 1; i <= 4; i++ {
+		r.PushBack(i)
+	}
+	r.PushFront(0)
+	if got, want := r.AppendTo(nil), []int{0, 2, 3}; !equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// TestDequeSteadyStateAllocs checks that once a Deque has grown to its
+// working size, pushing and popping at both ends doesn't allocate.
+func TestDequeSteadyStateAllocs(t *testing.T) {
+	d := vector.NewDeque[int](0)
+	allocs := testing.AllocsPerRun(1000, func() { // the warm-up run grows d
+		for i := 0; i < 64; i++ {
+			d.PushBack(i)
+			d.PushFront(-i)
+		}
+		for d.Len() > 0 {
+			d.PopFront()
+			d.PopBack()
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("%v allocations per run, want 0", allocs)
+	}
+
+	r := vector.NewRing[int](16)
+	allocs = testing.AllocsPerRun(1000, func() {
+		for i := 0; i < 64; i++ {
+			r.PushBack(i)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("ring: %v allocations per run, want 0", allocs)
+	}
+}
+
+// dequeLikeSlice replays random operations on a Deque and on a model slice
+// pushed and popped at both ends, and compares them after every step.
+func dequeLikeSlice(ops []uint16, values []int) bool {
+	d := vector.NewDeque[int](0)
+	var model []int
+
+	for k, op := range ops {
+		x := k
+		if len(values) > 0 {
+			x = values[k%len(values)]
+		}
+		i := int(op>>3) % (len(model) + 2)
+
+		switch op % 6 {
+		case 0:
+			d.PushBack(x)
+			model = append(model, x)
+		case 1:
+			d.PushFront(x)
+			model = append([]int{x}, model...)
+		case 2, 3:
+			var got int
+			var err error
+			if op%6 == 2 {
+				got, err = d.PopFront()
+			} else {
+				got, err = d.PopBack()
+			}
+			if len(model) == 0 {
+				if !errors.Is(err, vector.ErrEmpty) {
+					return false
+				}
+				break
+			}
+			want := model[0]
+			if op%6 == 2 {
+				model = model[1:]
+			} else {
+				want = model[len(model)-1]
+				model = model[:len(model)-1]
+			}
+			if err != nil || got != want {
+				return false
+			}
+		case 4:
+			err := d.Set(i, x)
+			if i >= len(model) {
+				if !errors.Is(err, vector.ErrOutOfRange) {
+					return false
+				}
+				break
+			}
+			model[i] = x
+		case 5:
+			got, err := d.Get(i)
+			if i >= len(model) {
+				if !errors.Is(err, vector.ErrOutOfRange) {
+					return false
+				}
+				break
+			}
+			if err != nil || got != model[i] {
+				return false
+			}
+		}
+
+		if d.Len() != len(model) || !equal(d.AppendTo(nil), model) {
+			return false
+		}
+	}
+
+	ok := true
+	d.All()(func(i int, x int) bool {
+		ok = model[i] == x
+		return ok
+	})
+	return ok
+}
@@ -0,0 +1,130 @@
+package vector
+
+import "math"
+
+// Growth decides how much a Vector reallocates when it runs out of room.
+type Growth interface {
+	// Grow returns the new capacity, at least need, of a vector of capacity
+	// cap that needs room for need elements of elemSize bytes.
+	Grow(cap, need int, elemSize uintptr) int
+}
+
+// Factor multiplies the capacity, plus one so an empty vector grows too.
+// Factor(2) is how appendInt grew: 1, 3, 7, 15...
+type Factor float64
+
+// The common factors. Doubling copies each element about once on average,
+// 1.5x wastes less memory and lets a freed block be reused by a later growth.
+const (
+	Doubling    = Factor(2)
+	OneAndAHalf = Factor(1.5)
+)
+
+func (f Factor) Grow(cap, need int, _ uintptr) int {
+	return max(int(float64(cap)*float64(f))+1, need)
+}
+
+// Increment grows by a fixed number of elements. Appending n elements one at
+// a time copies O(n²/Increment) of them, it suits vectors of known small size.
+type Increment int
+
+func (inc Increment) Grow(cap, need int, _ uintptr) int {
+	return max(cap+max(int(inc), 1), need)
+}
+
+// Runtime grows like the Go runtime's append (growslice since Go 1.20):
+// doubling up to 256 elements, then by a factor easing from 2 to 1.25.
+// Wrap it in SizeClass to also get the rounding up the allocator does.
+type Runtime struct{}
+
+func (Runtime) Grow(cap, need int, _ uintptr) int {
+	const threshold = 256
+	if double := cap + cap; need > double {
+		return need
+	} else if cap < threshold {
+		return max(double, need)
+	}
+	n := cap
+	for n < need {
+		// transition from 2x for small slices to 1.25x for large ones
+		n += (n + 3*threshold) >> 2
+		if n <= 0 { // overflowed, settle for need like growslice
+			return need
+		}
+	}
+	return n
+}
+
+// SizeClass rounds the capacity chosen by Base (Doubling when nil) up so
+// that it fills the block the Go allocator would hand out anyway.
+type SizeClass struct {
+	Base Growth
+}
+
+func (s SizeClass) Grow(cap, need int, elemSize uintptr) int {
+	base := s.Base
+	if base == nil {
+		base = Doubling
+	}
+	n := base.Grow(cap, need, elemSize)
+	if elemSize == 0 {
+		return n
+	}
+	if uintptr(n) > math.MaxInt/elemSize {
+		return n // the size overflows, no allocation will round it
+	}
+	c := roundUpSize(uintptr(n)*elemSize) / elemSize
+	if c > math.MaxInt {
+		return n
+	}
+	return int(c)
+}
+
+// sizeClasses are the small object size classes of the Go allocator
+// (runtime/sizeclasses.go), larger objects are rounded up to pages.
+var sizeClasses = []uintptr{
+	8, 16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240, 256,
+	288, 320, 352, 384, 416, 448, 480, 512, 576, 640, 704, 768, 896, 1024, 1152, 1280,
+	1408, 1536, 1792, 2048, 2304, 2688, 3072, 3200, 3456, 4096, 4864, 5376, 6144, 6528,
+	6784, 6912, 8192, 9472, 9728, 10240, 10880, 12288, 13568, 14336, 16384, 18432, 19072,
+	20480, 21760, 24576, 27264, 28672, 32768,
+}
+
+const pageSize = 8192
+
+func roundUpSize(n uintptr) uintptr {
+	for _, c := range sizeClasses {
+		if n <= c {
+			return c
+		}
+	}
+	return (n + pageSize - 1) &^ (pageSize - 1)
+}
+
+// Stats is the allocation telemetry of a Vector or a Deque.
+type Stats struct {
+	// Reallocations counts the backing arrays allocated after the first one.
+	Reallocations int
+	// BytesCopied is what the reallocations copied from the old arrays.
+	BytesCopied int64
+	// BytesAllocated is the size of every backing array allocated, the first one included.
+	BytesAllocated int64
+	// PeakWasted is the largest number of unused slots seen right after a growth,
+	// PeakWastedBytes the same in bytes.
+	PeakWasted      int
+	PeakWastedBytes int64
+}
+
+// record accounts for moving n elements of size bytes from an array of
+// oldCap elements to a new one of capacity, need of them about to be used.
+func (s *Stats) record(oldCap, n, capacity, need int, size uintptr) {
+	if oldCap > 0 {
+		s.Reallocations++
+		s.BytesCopied += int64(n) * int64(size)
+	}
+	s.BytesAllocated += int64(capacity) * int64(size)
+	if waste := capacity - need; waste > s.PeakWasted {
+		s.PeakWasted = waste
+		s.PeakWastedBytes = int64(waste) * int64(size)
+	}
+}
@@ -0,0 +1,209 @@
+package vector_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"main/slices/vector"
+)
+
+// The growth benchmarks compare the strategies on a few workloads, for the
+// speed and, through the vectors' Stats, for what each strategy reallocates,
+// copies and wastes:
+//
+//	go test ./slices/vector -run '^$' -bench Growth -benchtime 500ms
+
+var strategies = []struct {
+	name string
+	g    vector.Growth
+}{
+	{"doubling", vector.Doubling},
+	{"1.5x", vector.OneAndAHalf},
+	{"runtime", vector.Runtime{}},
+	{"runtime+sizeclass", vector.SizeClass{Base: vector.Runtime{}}},
+	{"doubling+sizeclass", vector.SizeClass{}},
+	{"increment64", vector.Increment(64)},
+}
+
+// benchmarkGrowth runs the workload run, which fills vectors growing by g
+// and returns their combined Stats, with every strategy.
+func benchmarkGrowth(b *testing.B, run func(g vector.Growth) vector.Stats) {
+	for _, s := range strategies {
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var st vector.Stats
+			for i := 0; i < b.N; i++ {
+				st = run(s.g)
+			}
+			b.ReportMetric(float64(st.Reallocations), "reallocs/op")
+			b.ReportMetric(float64(st.BytesCopied), "copied-B/op")
+			b.ReportMetric(float64(st.BytesAllocated), "allocated-B/op")
+			b.ReportMetric(float64(st.PeakWastedBytes), "peak-waste-B")
+		})
+	}
+}
+
+// BenchmarkGrowthInts pushes 100k ints one at a time.
+func BenchmarkGrowthInts(b *testing.B) {
+	benchmarkGrowth(b, func(g vector.Growth) vector.Stats {
+		v := vector.NewWithGrowth[int](0, g)
+		for i := 0; i < 100_000; i++ {
+			v.Push(i)
+		}
+		return v.Stats()
+	})
+}
+
+type record [8]int64 // a 64 byte struct
+
+// BenchmarkGrowthRecords pushes 10k 64 byte records.
+func BenchmarkGrowthRecords(b *testing.B) {
+	benchmarkGrowth(b, func(g vector.Growth) vector.Stats {
+		v := vector.NewWithGrowth[record](0, g)
+		for i := 0; i < 10_000; i++ {
+			v.Push(record{int64(i)})
+		}
+		return v.Stats()
+	})
+}
+
+// BenchmarkGrowthSmallVectors fills 1k vectors of 20 strings.
+func BenchmarkGrowthSmallVectors(b *testing.B) {
+	benchmarkGrowth(b, func(g vector.Growth) vector.Stats {
+		var total vector.Stats
+		for i := 0; i < 1000; i++ {
+			v := vector.NewWithGrowth[string](0, g)
+			for j := 0; j < 20; j++ {
+				v.Push("log line")
+			}
+			total = add(total, v.Stats())
+		}
+		return total
+	})
+}
+
+// BenchmarkGrowthBursts pushes bursts of 1 to 256 ints.
+func BenchmarkGrowthBursts(b *testing.B) {
+	benchmarkGrowth(b, func(g vector.Growth) vector.Stats {
+		r := rand.New(rand.NewSource(1)) // same bursts for every strategy
+		burst := make([]int, 256)
+		v := vector.NewWithGrowth[int](0, g)
+		for i := 0; i < 1000; i++ {
+			v.Push(burst[:1+r.Intn(len(burst))]...)
+		}
+		return v.Stats()
+	})
+}
+
+// BenchmarkGrowthChurn uses a vector as a stack, pushing 3 and popping 2.
+func BenchmarkGrowthChurn(b *testing.B) {
+	benchmarkGrowth(b, func(g vector.Growth) vector.Stats {
+		v := vector.NewWithGrowth[int](0, g)
+		for i := 0; i < 30_000; i++ {
+			v.Push(i, i, i)
+			v.Pop()
+			v.Pop()
+		}
+		return v.Stats()
+	})
+}
+
+func add(a, b vector.Stats) vector.Stats {
+	a.Reallocations += b.Reallocations
+	a.BytesCopied += b.BytesCopied
+	a.BytesAllocated += b.BytesAllocated
+	if b.PeakWasted > a.PeakWasted {
+		a.PeakWasted, a.PeakWastedBytes = b.PeakWasted, b.PeakWastedBytes
+	}
+	return a
+}
+
+var elemSizes = []uintptr{0, 1, 8, 24, 1000}
+
+// TestGrowthAtLeastNeed checks that every strategy makes room for what is needed.
+func TestGrowthAtLeastNeed(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, s := range strategies {
+		for i := 0; i < 2000; i++ {
+			capacity := r.Intn(1 << 20)
+			need := capacity + 1 + r.Intn(1<<r.Intn(21))
+			size := elemSizes[r.Intn(len(elemSizes))]
+			if got := s.g.Grow(capacity, need, size); got < need {
+				t.Fatalf("%s: Grow(%d, %d, %d) = %d", s.name, capacity, need, size, got)
+			}
+		}
+	}
+}
+
+// TestGrowthNonDecreasing checks that Runtime and SizeClass never shrink a
+// vector, and grow one pushed one element at a time. They aren't monotonic
+// in need: like growslice, Runtime jumps to need past twice the capacity.
+func TestGrowthNonDecreasing(t *testing.T) {
+	for _, s := range strategies[2:5] {
+		for _, size := range elemSizes {
+			for _, capacity := range []int{0, 1, 5, 255, 256, 257, 1000, 1 << 20} {
+				for need := capacity + 1; need < capacity+3000; need++ {
+					if got := s.g.Grow(capacity, need, size); got < capacity || got < need {
+						t.Fatalf("%s: Grow(%d, %d, %d) = %d", s.name, capacity, need, size, got)
+					}
+				}
+			}
+
+			capacity := 0
+			for i := 0; i < 100 && capacity < 1<<40; i++ {
+				next := s.g.Grow(capacity, capacity+1, size)
+				if next <= capacity {
+					t.Fatalf("%s: Grow(%d, %d, %d) = %d", s.name, capacity, capacity+1, size, next)
+				}
+				capacity = next
+			}
+		}
+	}
+}
+
+// TestGrowthHuge checks the capacities near the limits of an int, where the
+// computations overflow.
+func TestGrowthHuge(t *testing.T) {
+	for _, c := range []struct {
+		name           string
+		g              vector.Growth
+		capacity, need int
+		size           uintptr
+	}{
+		{"runtime", vector.Runtime{}, 1 << 61, math.MaxInt, 1},
+		{"runtime", vector.Runtime{}, math.MaxInt / 2, math.MaxInt/2 + 1, 1},
+		{"sizeclass", vector.SizeClass{Base: vector.Runtime{}}, 1 << 40, 1 << 58, 64},
+		{"sizeclass", vector.SizeClass{Base: vector.Runtime{}}, 1 << 31, 1<<31 + 1, 1},
+		{"sizeclass", vector.SizeClass{Base: vector.Increment(1)}, 0, math.MaxInt - 10, 1},
+	} {
+		if got := c.g.Grow(c.capacity, c.need, c.size); got < c.need {
+			t.Errorf("%s: Grow(%d, %d, %d) = %d", c.name, c.capacity, c.need, c.size, got)
+		}
+	}
+
+	// beyond 2³¹ elements SizeClass still rounds to whole pages
+	if got := (vector.SizeClass{Base: vector.Increment(1)}).Grow(0, 1<<31+1, 1); got != 1<<31+8192 {
+		t.Errorf("Grow of 2³¹+1 bytes = %d, want %d", got, 1<<31+8192)
+	}
+}
+
+func TestSizeClass(t *testing.T) {
+	g := vector.SizeClass{Base: vector.Increment(1)}
+	for _, c := range []struct {
+		need int
+		size uintptr
+		want int
+	}{
+		{1, 1, 8},
+		{3, 8, 3},     // 24 bytes is a size class
+		{5, 8, 6},     // 40 bytes round up to 48
+		{3, 24, 3},    // 72 bytes round up to 80, 3 elements
+		{100, 0, 100}, // zero-size elements aren't rounded
+		{33000, 1, 40960},
+	} {
+		if got := g.Grow(0, c.need, c.size); got != c.want {
+			t.Errorf("Grow(0, %d, %d) = %d, want %d", c.need, c.size, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package vector is a growable array, the generic version of the appendInt,
+// appendString and appendBool helpers that used to live in slices/slices.go.
+//
+// Unlike a slice, a Vector is used through a pointer, so growing it never
+// leaves a caller holding a stale copy of the header. Deque is the same kind
+// of storage used as a circular buffer, for queues.
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrEmpty is returned by Pop on an empty Vector.
+var ErrEmpty = errors.New("vector: empty")
+
+// ErrOutOfRange is matched by the *IndexError of out of range accesses.
+var ErrOutOfRange = errors.New("vector: index out of range")
+
+// IndexError reports an index outside of [0, Len).
+type IndexError struct {
+	Op    string // the method, e.g. "Get"
+	Index int
+	Len   int
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("vector: %s: index %d out of range [0:%d]", e.Op, e.Index, e.Len)
+}
+
+func (e *IndexError) Unwrap() error { return ErrOutOfRange }
+
+// Vector is a growable array of T. The zero value is an empty Vector ready to use,
+// growing by Doubling.
+type Vector[T any] struct {
+	data   []T
+	growth Growth
+	stats  Stats
+}
+
+// New returns an empty Vector with room for capacity elements.
+func New[T any](capacity int) *Vector[T] {
+	return NewWithGrowth[T](capacity, nil)
+}
+
+// NewWithGrowth returns an empty Vector with room for capacity elements that
+// grows according to g, Doubling when nil.
+func NewWithGrowth[T any](capacity int, g Growth) *Vector[T] {
+	v := &Vector[T]{growth: g}
+	if capacity > 0 {
+		v.realloc(capacity, capacity)
+	}
+	return v
+}
+
+// Of returns a Vector holding a copy of values.
+func Of[T any](values ...T) *Vector[T] {
+	v := New[T](len(values))
+	v.data = append(v.data, values...)
+	return v
+}
+
+// SetGrowth changes how v grows from now on, Doubling when g is nil.
+func (v *Vector[T]) SetGrowth(g Growth) { v.growth = g }
+
+// Stats returns the allocation telemetry of v since it was created.
+func (v *Vector[T]) Stats() Stats { return v.stats }
+
+// Len returns the number of elements.
+func (v *Vector[T]) Len() int { return len(v.data) }
+
+// Cap returns the number of elements v can hold without reallocating.
+func (v *Vector[T]) Cap() int { return cap(v.data) }
+
+// Push appends values to the end of v.
+func (v *Vector[T]) Push(values ...T) {
+	n := len(v.data)
+	v.grow(len(values))
+	v.data = v.data[:n+len(values)]
+	copy(v.data[n:], values)
+}
+
+// Pop removes and returns the last element.
+func (v *Vector[T]) Pop() (T, error) {
+	var zero T
+	if len(v.data) == 0 {
+		return zero, ErrEmpty
+	}
+	last := len(v.data) - 1
+	x := v.data[last]
+	v.data[last] = zero // don't keep what it points to alive
+	v.data = v.data[:last]
+	return x, nil
+}
+
+// Insert inserts values at index i, shifting the elements from i up.
+// i may be Len to append.
+func (v *Vector[T]) Insert(i int, values ...T) error {
+	n := len(v.data)
+	if i < 0 || i > n {
+		return &IndexError{"Insert", i, n}
+	}
+	v.grow(len(values))
+	v.data = v.data[:n+len(values)]
+	copy(v.data[i+len(values):], v.data[i:n])
+	copy(v.data[i:], values)
+	return nil
+}
+
+// Remove removes and returns the element at index i, shifting the following ones down.
+func (v *Vector[T]) Remove(i int) (T, error) {
+	var zero T
+	n := len(v.data)
+	if i < 0 || i >= n {
+		return zero, &IndexError{"Remove", i, n}
+	}
+	x := v.data[i]
+	copy(v.data[i:], v.data[i+1:])
+	v.data[n-1] = zero
+	v.data = v.data[:n-1]
+	return x, nil
+}
+
+// Get returns the element at index i.
+func (v *Vector[T]) Get(i int) (T, error) {
+	if i < 0 || i >= len(v.data) {
+		var zero T
+		return zero, &IndexError{"Get", i, len(v.data)}
+	}
+	return v.data[i], nil
+}
+
+// Set replaces the element at index i.
+func (v *Vector[T]) Set(i int, x T) error {
+	if i < 0 || i >= len(v.data) {
+		return &IndexError{"Set", i, len(v.data)}
+	}
+	v.data[i] = x
+	return nil
+}
+
+// Reserve makes room for at least n more elements, so the next n pushes don't reallocate.
+func (v *Vector[T]) Reserve(n int) {
+	if n > cap(v.data)-len(v.data) {
+		v.realloc(len(v.data)+n, len(v.data)+n)
+	}
+}
+
+// ShrinkToFit reallocates v so that Cap equals Len, releasing the unused capacity.
+func (v *Vector[T]) ShrinkToFit() {
+	if cap(v.data) > len(v.data) {
+		v.realloc(len(v.data), len(v.data))
+	}
+}
+
+// Clone returns a copy of v with no spare capacity, growing like v and with fresh Stats.
+func (v *Vector[T]) Clone() *Vector[T] {
+	c := NewWithGrowth[T](len(v.data), v.growth)
+	c.data = append(c.data, v.data...)
+	return c
+}
+
+// Slice returns the elements of v. The slice shares v's storage, so it is only
+// valid until the next call that changes the length of v.
+func (v *Vector[T]) Slice() []T {
+	return v.data[:len(v.data):len(v.data)]
+}
+
+// All iterates over the indexes and elements of v. It has the shape of Go 1.23's
+// iter.Seq2, in Go 1.21 call it with a callback that returns false to stop:
+//
+//	v.All()(func(i int, x T) bool {
+//		fmt.Println(i, x)
+//		return true
+//	})
+func (v *Vector[T]) All() func(yield func(int, T) bool) {
+	return func(yield func(int, T) bool) {
+		for i, x := range v.data {
+			if !yield(i, x) {
+				return
+			}
+		}
+	}
+}
+
+// grow makes room for n more elements, reallocating as v.growth decides.
+func (v *Vector[T]) grow(n int) {
+	need := len(v.data) + n
+	if need <= cap(v.data) {
+		return
+	}
+	g := v.growth
+	if g == nil {
+		g = Doubling
+	}
+	v.realloc(max(g.Grow(cap(v.data), need, v.elemSize()), need), need)
+}
+
+// realloc moves the elements to a new backing array of capacity elements,
+// need is the length v will have once the caller is done, to measure waste.
+func (v *Vector[T]) realloc(capacity, need int) {
+	v.stats.record(cap(v.data), len(v.data), capacity, need, v.elemSize())
+
+	data := make([]T, len(v.data), capacity)
+	copy(data, v.data)
+	v.data = data
+}
+
+func (v *Vector[T]) elemSize() uintptr {
+	var zero T
+	return unsafe.Sizeof(zero)
+}
@@ -0,0 +1,167 @@
+package vector_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"main/slices/vector"
+)
+
+// check runs the property f with random inputs generated by testing/quick.
+func check(t *testing.T, f any) {
+	t.Helper()
+	if err := quick.Check(f, &quick.Config{MaxCount: 500}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestVectorSameAsAppend checks Vector against the built-in append.
+func TestVectorSameAsAppend(t *testing.T) {
+	check(t, sameAsAppend)
+}
+
+func TestVectorPushKeepsOrder(t *testing.T) {
+	check(t, func(xs []int) bool {
+		v := vector.New[int](0)
+		for _, x := range xs {
+			v.Push(x)
+		}
+		return equal(v.Slice(), xs) && v.Cap() >= v.Len()
+	})
+}
+
+func TestVectorCloneIsIndependent(t *testing.T) {
+	check(t, func(xs []int, x int) bool {
+		v := vector.Of(xs...)
+		c := v.Clone()
+		c.Push(x)
+		if c.Len() > 1 {
+			c.Set(0, x+1)
+		}
+		return equal(v.Slice(), xs)
+	})
+}
+
+// TestVectorOutOfRange checks that out of range indexes are errors, not panics.
+func TestVectorOutOfRange(t *testing.T) {
+	check(t, func(xs []int, i int) bool {
+		v := vector.Of(xs...)
+		if i >= 0 && i < len(xs) {
+			i = -i - 1
+		}
+		_, getErr := v.Get(i)
+		_, removeErr := v.Remove(i)
+		return errors.Is(getErr, vector.ErrOutOfRange) &&
+			errors.Is(v.Set(i, 0), vector.ErrOutOfRange) &&
+			errors.Is(removeErr, vector.ErrOutOfRange) &&
+			equal(v.Slice(), xs)
+	})
+}
+
+// TestVectorReserve checks that Reserve(n) makes the next n pushes allocation free.
+func TestVectorReserve(t *testing.T) {
+	check(t, func(xs []int, n uint8) bool {
+		v := vector.Of(xs...)
+		v.Reserve(int(n))
+		c := v.Cap()
+		for i := 0; i < int(n); i++ {
+			v.Push(i)
+		}
+		return v.Cap() == c
+	})
+}
+
+// TestVectorShrinkToFit checks that ShrinkToFit drops the spare capacity only.
+func TestVectorShrinkToFit(t *testing.T) {
+	check(t, func(xs []int, extra uint8) bool {
+		v := vector.Of(xs...)
+		v.Reserve(int(extra))
+		v.ShrinkToFit()
+		return v.Cap() == v.Len() && equal(v.Slice(), xs)
+	})
+}
+
+// sameAsAppend replays random operations on a Vector and on a model slice
+// that only uses append and slicing, and compares them after every step.
+func sameAsAppend(ops []uint16, values []int) bool {
+	v := vector.New[int](0)
+	var model []int
+
+	for k, op := range ops {
+		x := k
+		if len(values) > 0 {
+			x = values[k%len(values)]
+		}
+		i := int(op>>3) % (len(model) + 2) // sometimes one past the valid range
+
+		switch op % 6 {
+		case 0:
+			v.Push(x)
+			model = append(model, x)
+		case 1:
+			got, err := v.Pop()
+			if len(model) == 0 {
+				if !errors.Is(err, vector.ErrEmpty) {
+					return false
+				}
+				break
+			}
+			if err != nil || got != model[len(model)-1] {
+				return false
+			}
+			model = model[:len(model)-1]
+		case 2:
+			err := v.Insert(i, x, x+1)
+			if i > len(model) {
+				if err == nil {
+					return false
+				}
+				break
+			}
+			model = append(model[:i], append([]int{x, x + 1}, model[i:]...)...)
+		case 3:
+			got, err := v.Remove(i)
+			if i >= len(model) {
+				if err == nil {
+					return false
+				}
+				break
+			}
+			if err != nil || got != model[i] {
+				return false
+			}
+			model = append(model[:i], model[i+1:]...)
+		case 4:
+			err := v.Set(i, x)
+			if i >= len(model) {
+				if err == nil {
+					return false
+				}
+				break
+			}
+			model[i] = x
+		case 5:
+			v.Reserve(i)
+		}
+
+		if v.Len() != len(model) || !equal(v.Slice(), model) {
+			return false
+		}
+	}
+
+	// All must visit what Get returns
+	ok := true
+	v.All()(func(i int, x int) bool {
+		got, err := v.Get(i)
+		ok = err == nil && got == x
+		return ok
+	})
+	return ok
+}
+
+// equal compares a and b, treating nil and empty alike.
+func equal(a, b []int) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}